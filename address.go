@@ -0,0 +1,340 @@
+package ical
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Calendar user addresses (CAL-ADDRESS) are URIs, usually mailto: URIs of an email address.
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-3-3-calendar-user-address.html
+
+var uriSchemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+const (
+	maxLocalPartOctets = 64
+	maxDomainOctets    = 253
+	maxLabelOctets     = 63
+)
+
+// validateCalAddress reports whether address is an email address or a calendar user address URI,
+// e.g. "mailto:jane@example.com", "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6" or "sip:room1@example.com".
+func validateCalAddress(address string) bool {
+	scheme, rest, isURI := splitURIScheme(address)
+	if !isURI {
+		return validateEmail(address)
+	}
+	if strings.EqualFold(scheme, "mailto") {
+		email, err := url.PathUnescape(rest)
+		return err == nil && validateEmail(email)
+	}
+	if rest == "" || strings.ContainsAny(rest, " \t\r\n\"") {
+		return false
+	}
+	_, err := url.Parse(address)
+	return err == nil
+}
+
+// validateEmail reports whether email is a single RFC 5322 addr-spec, without a display name.
+//
+// Domains may be internationalized, e.g. "jörg@bücher.example".
+func validateEmail(email string) bool {
+	at := strings.LastIndexByte(email, '@')
+	if at <= 0 || at == len(email)-1 {
+		return false
+	}
+	local, domain := email[:at], email[at+1:]
+	if !validLocalPart(local) || !validDomain(domain) {
+		return false
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" {
+		return false
+	}
+	return true
+}
+
+// The calendar user address URI for an email address or URI.
+//
+// Email addresses are written as mailto: URIs, with the local part percent-encoded
+// and the domain converted to its ASCII (punycode) form.
+// https://www.rfc-editor.org/rfc/rfc6068
+func calAddress(address string) string {
+	if _, _, isURI := splitURIScheme(address); isURI {
+		return address
+	}
+
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return "mailto:" + address
+	}
+	domain, err := domainToASCII(address[at+1:])
+	if err != nil {
+		domain = address[at+1:]
+	}
+	return "mailto:" + percentEncodeMailto(address[:at]) + "@" + domain
+}
+
+// The email address of a mailto: calendar user address, other URIs are returned as they are.
+//
+// The reverse of calAddress, e.g. "mailto:john%20doe@example.com" becomes "john doe@example.com"
+func addressFromCalAddress(address string) string {
+	scheme, rest, isURI := splitURIScheme(address)
+	if !isURI || !strings.EqualFold(scheme, "mailto") {
+		return address
+	}
+	email, err := url.PathUnescape(rest)
+	if err != nil {
+		return rest
+	}
+	return email
+}
+
+// Quoted, comma separated CAL-ADDRESS parameter values
+func calAddressList(addresses []string) string {
+	quoted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		quoted = append(quoted, `"`+calAddress(address)+`"`)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// Splits "scheme:rest". An email address is never mistaken for a URI,
+// since a colon is not allowed in an unquoted local part.
+func splitURIScheme(address string) (scheme, rest string, ok bool) {
+	if !uriSchemePattern.MatchString(address) {
+		return "", "", false
+	}
+	i := strings.IndexByte(address, ':')
+	return address[:i], address[i+1:], true
+}
+
+func validLocalPart(local string) bool {
+	if len(local) > maxLocalPartOctets {
+		return false
+	}
+
+	// quoted-string, e.g. "john doe"
+	if len(local) >= 2 && strings.HasPrefix(local, `"`) && strings.HasSuffix(local, `"`) {
+		inner := local[1 : len(local)-1]
+		for i := 0; i < len(inner); i++ {
+			c := inner[i]
+			if c == '\\' {
+				if i == len(inner)-1 {
+					return false
+				}
+				i++
+				continue
+			}
+			if c == '"' || c < 0x20 || c == 0x7f {
+				return false
+			}
+		}
+		return true
+	}
+
+	// dot-atom, e.g. john.doe
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return false
+		}
+		for _, r := range atom {
+			if !isAtext(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// atext as defined by RFC 5322, extended with UTF-8 by RFC 6532
+func isAtext(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case r >= utf8.RuneSelf:
+		return r != utf8.RuneError
+	}
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+func validDomain(domain string) bool {
+	// domain-literal, e.g. [192.0.2.1] or [IPv6:2001:db8::1]
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		literal := domain[1 : len(domain)-1]
+		if v6, ok := strings.CutPrefix(literal, "IPv6:"); ok {
+			ip := net.ParseIP(v6)
+			return ip != nil && ip.To4() == nil
+		}
+		ip := net.ParseIP(literal)
+		return ip != nil && ip.To4() != nil
+	}
+
+	ascii, err := domainToASCII(domain)
+	if err != nil || len(ascii) > maxDomainOctets {
+		return false
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if label == "" || len(label) > maxLabelOctets {
+			return false
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, c := range []byte(label) {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Converts an internationalized domain name to its ASCII form,
+// e.g. "bücher.example" to "xn--bcher-kva.example"
+//
+// https://www.rfc-editor.org/rfc/rfc5891
+func domainToASCII(domain string) (string, error) {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		encoded, err := punycodeEncode(strings.ToLower(label))
+		if err != nil {
+			return "", err
+		}
+		labels[i] = "xn--" + encoded
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// Percent-encodes every octet of the local part that is not allowed in a mailto: URI
+func percentEncodeMailto(local string) string {
+	const allowed = "-._~!$'()*+,;:"
+	var builder strings.Builder
+	for _, c := range []byte(local) {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte(allowed, c) >= 0 {
+			builder.WriteByte(c)
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%%%02X", c))
+	}
+	return builder.String()
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Punycode parameters
+//
+// https://www.rfc-editor.org/rfc/rfc3492#section-5
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// Encodes a single label with the Punycode algorithm of RFC 3492
+func punycodeEncode(label string) (string, error) {
+	if !utf8.ValidString(label) {
+		return "", fmt.Errorf("punycode: invalid UTF-8 in %q", label)
+	}
+	runes := []rune(label)
+
+	var output []byte
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			output = append(output, byte(r))
+		}
+	}
+	basic := len(output)
+	handled := basic
+	if basic > 0 {
+		output = append(output, '-')
+	}
+
+	n, delta, bias := punyInitialN, 0, punyInitialBias
+	for handled < len(runes) {
+		next := math.MaxInt32
+		for _, r := range runes {
+			if int(r) >= n && int(r) < next {
+				next = int(r)
+			}
+		}
+		if (next - n) > (math.MaxInt32-delta)/(handled+1) {
+			return "", fmt.Errorf("punycode: overflow encoding %q", label)
+		}
+		delta += (next - n) * (handled + 1)
+		n = next
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := k - bias
+				if t < punyTMin {
+					t = punyTMin
+				} else if t > punyTMax {
+					t = punyTMax
+				}
+				if q < t {
+					break
+				}
+				output = append(output, punycodeDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			output = append(output, punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	return string(output), nil
+}
+
+func punycodeAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
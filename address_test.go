@@ -0,0 +1,136 @@
+package ical
+
+import "testing"
+
+func TestValidateEmail(t *testing.T) {
+	var tests = []struct {
+		email    string
+		expected bool
+	}{
+		{"john.doe@example.com", true},
+		{"john+tag@example.co.uk", true},
+		{`"john doe"@example.com`, true},
+		{"jörg@bücher.example", true},
+		{"user@[192.0.2.1]", true},
+		{"user@[IPv6:2001:db8::1]", true},
+		{"a@localhost", true},
+		{"a@b@c", false},
+		{"a b@example.com", false},
+		{"john..doe@example.com", false},
+		{".john@example.com", false},
+		{"john@-example.com", false},
+		{"john@example..com", false},
+		{"John <john@example.com>", false},
+		{"john@example.com (John)", false},
+		{"john@exa mple.com", false},
+		{"@example.com", false},
+		{"john@", false},
+		{"invalid-email", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := validateEmail(tt.email); got != tt.expected {
+			t.Errorf("validateEmail(%q) = %v, want %v", tt.email, got, tt.expected)
+		}
+	}
+}
+
+func TestValidateCalAddress(t *testing.T) {
+	var tests = []struct {
+		address  string
+		expected bool
+	}{
+		{"jane@example.com", true},
+		{"mailto:jane@example.com", true},
+		{"MAILTO:jane%20doe@example.com", false},
+		{"mailto:%22jane%20doe%22@example.com", true},
+		{"urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", true},
+		{"sip:room1@example.com", true},
+		{"tel:+1-919-555-1234", true},
+		{"urn:", false},
+		{"sip:room 1@example.com", false},
+		{"mailto:a@b@c", false},
+	}
+	for _, tt := range tests {
+		if got := validateCalAddress(tt.address); got != tt.expected {
+			t.Errorf("validateCalAddress(%q) = %v, want %v", tt.address, got, tt.expected)
+		}
+	}
+}
+
+func TestCalAddress(t *testing.T) {
+	var tests = []struct {
+		address  string
+		expected string
+	}{
+		{"jane@example.com", "mailto:jane@example.com"},
+		{`"jane doe"@example.com`, "mailto:%22jane%20doe%22@example.com"},
+		{"a/b?c#d@example.com", "mailto:a%2Fb%3Fc%23d@example.com"},
+		{"100%@example.com", "mailto:100%25@example.com"},
+		{"jörg@Bücher.example", "mailto:j%C3%B6rg@xn--bcher-kva.example"},
+		{"urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6"},
+		{"mailto:jane@example.com", "mailto:jane@example.com"},
+	}
+	for _, tt := range tests {
+		if got := calAddress(tt.address); got != tt.expected {
+			t.Errorf("calAddress(%q) = %q, want %q", tt.address, got, tt.expected)
+		}
+	}
+}
+
+func TestAddressFromCalAddress(t *testing.T) {
+	var tests = []struct {
+		address  string
+		expected string
+	}{
+		{"mailto:jane@example.com", "jane@example.com"},
+		{"MAILTO:jane@example.com", "jane@example.com"},
+		{"mailto:%22jane%20doe%22@example.com", `"jane doe"@example.com`},
+		{"urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6"},
+		{"jane@example.com", "jane@example.com"},
+	}
+	for _, tt := range tests {
+		if got := addressFromCalAddress(tt.address); got != tt.expected {
+			t.Errorf("addressFromCalAddress(%q) = %q, want %q", tt.address, got, tt.expected)
+		}
+	}
+}
+
+func TestPunycodeEncode(t *testing.T) {
+	// Samples from RFC 3492 section 7.1 and common IDN test vectors
+	var tests = []struct {
+		label    string
+		expected string
+	}{
+		{"bücher", "bcher-kva"},
+		{"münchen", "mnchen-3ya"},
+		{"ü", "tda"},
+		{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"3年b組金八先生", "3b-ww4c5e180e575a65lsy2b"},
+	}
+	for _, tt := range tests {
+		got, err := punycodeEncode(tt.label)
+		if err != nil {
+			t.Errorf("punycodeEncode(%q) returned error: %v", tt.label, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("punycodeEncode(%q) = %q, want %q", tt.label, got, tt.expected)
+		}
+	}
+}
+
+func TestParticipantCalAddress(t *testing.T) {
+	room := Participant{Name: "Room 1", Email: "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", Type: RoomUser}
+	if !room.valid() {
+		t.Errorf("Expected participant with urn:uuid address to be valid")
+	}
+
+	event := mockEvent()
+	if err := event.AddAttendee("Phone", "sip:conference@example.com"); err != nil {
+		t.Errorf("AddAttendee() returned error for sip: address: %v", err)
+	}
+	if err := event.AddAttendee("Broken", "a@b@c"); err != ErrInvalidEmail {
+		t.Errorf("AddAttendee() = %v, want %v", err, ErrInvalidEmail)
+	}
+}
@@ -0,0 +1,171 @@
+package ical
+
+import (
+	"sort"
+	"time"
+)
+
+// A reminder firing for one occurrence of an event or To-Do
+type Alarm struct {
+	// The *Event or *Todo the reminder belongs to
+	Component Component
+
+	// Start of the occurrence the reminder fires for
+	//
+	// For To-Dos this is the start date, or the due date if there is no start date.
+	Occurrence time.Time
+
+	// The reminder that fires
+	Reminder *Reminder
+
+	// When the reminder fires, including repeats
+	FireTime time.Time
+}
+
+// A single occurrence of a component, with its start and end
+type occurrence struct {
+	start time.Time
+	end   time.Time
+}
+
+// Alarms returns every reminder of the calendar's events and To-Dos that fires
+// from from (inclusive) until to (exclusive), sorted by fire time.
+//
+// Each occurrence of a recurring event fires its reminders, except on exception dates.
+// Repeats are included, and fire times at or before a reminder's Acknowledged time are skipped.
+// A reminder with an absolute TriggerAt fires once, for the first occurrence.
+func (c *Calendar) Alarms(from, to time.Time) []Alarm {
+	var alarms []Alarm
+	for i := range c.Events {
+		event := &c.Events[i]
+		alarms = append(alarms, reminderAlarms(event, event.Reminders, event.occurrences(), from, to)...)
+	}
+	for i := range c.Todos {
+		todo := &c.Todos[i]
+		alarms = append(alarms, reminderAlarms(todo, todo.Reminders, todo.occurrences(), from, to)...)
+	}
+
+	sort.SliceStable(alarms, func(i, j int) bool {
+		return alarms[i].FireTime.Before(alarms[j].FireTime)
+	})
+	return alarms
+}
+
+func reminderAlarms(component Component, reminders []Reminder, occurrences []occurrence, from, to time.Time) []Alarm {
+	var alarms []Alarm
+	if len(occurrences) == 0 {
+		return alarms
+	}
+
+	for i := range reminders {
+		reminder := &reminders[i]
+		for _, occ := range occurrences {
+			var fire time.Time
+			switch {
+			case reminder.TriggerAt != nil:
+				fire = *reminder.TriggerAt
+			case reminder.relatedToEnd():
+				fire = occ.end.Add(reminder.Trigger)
+			default:
+				fire = occ.start.Add(reminder.Trigger)
+			}
+
+			for _, fireTime := range reminder.fireTimes(fire) {
+				if fireTime.Before(from) || !fireTime.Before(to) {
+					continue
+				}
+				if reminder.Acknowledged != nil && !fireTime.After(*reminder.Acknowledged) {
+					continue
+				}
+				alarms = append(alarms, Alarm{
+					Component:  component,
+					Occurrence: occ.start,
+					Reminder:   reminder,
+					FireTime:   fireTime,
+				})
+			}
+
+			// An absolute trigger fires once, not once per occurrence
+			if reminder.TriggerAt != nil {
+				break
+			}
+		}
+	}
+	return alarms
+}
+
+// The first fire time followed by every repeat
+func (r *Reminder) fireTimes(first time.Time) []time.Time {
+	times := []time.Time{first}
+	if r.Repeat != nil {
+		for i := 1; i <= *r.Repeat; i++ {
+			times = append(times, first.Add(time.Duration(i)*r.repeatInterval()))
+		}
+	}
+	return times
+}
+
+// Every occurrence of the event, skipping exception dates of recurrences
+func (e *Event) occurrences() []occurrence {
+	if !e.HasRecurrences() {
+		return []occurrence{{start: e.StartDate, end: e.End()}}
+	}
+
+	startDate, endDate := e.TimeZone.localTime(e.StartDate), e.TimeZone.localTime(e.EndDate)
+	var occurrences []occurrence
+	for i := range e.Recurrences {
+		rec := &e.Recurrences[i]
+		for _, start := range rec.Occurrences(startDate, endDate) {
+			if rec.isException(start) {
+				continue
+			}
+			occurrences = append(occurrences, occurrence{start: start, end: rec.occurrenceEnd(start)})
+		}
+	}
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].start.Before(occurrences[j].start)
+	})
+	return occurrences
+}
+
+// The To-Do as a single occurrence, from its start date (or due date) to its due date (or start date)
+//
+// A recurring To-Do is its current instance
+func (t *Todo) occurrences() []occurrence {
+	if t.Recurrence != nil {
+		start, err := t.firstInstance()
+		if err != nil {
+			return nil
+		}
+		return []occurrence{{start: start, end: t.Recurrence.occurrenceEnd(start)}}
+	}
+
+	switch {
+	case t.StartDate != nil && t.Due != nil:
+		return []occurrence{{start: *t.StartDate, end: *t.Due}}
+	case t.Due != nil:
+		return []occurrence{{start: *t.Due, end: *t.Due}}
+	case t.StartDate != nil:
+		return []occurrence{{start: *t.StartDate, end: *t.StartDate}}
+	default:
+		return nil
+	}
+}
+
+// End of the occurrence starting at start
+func (r *Recurrences) occurrenceEnd(start time.Time) time.Time {
+	if r.Duration != nil {
+		return r.Duration.AddTo(start)
+	}
+	return start.Add(r.EndTime.Sub(r.StartTime))
+}
+
+// Whether the occurrence falls on one of the exception dates
+func (r *Recurrences) isException(occurrence time.Time) bool {
+	for _, ex := range r.Exceptions {
+		if ex.Year() == occurrence.Year() && ex.Month() == occurrence.Month() && ex.Day() == occurrence.Day() {
+			return true
+		}
+	}
+	return false
+}
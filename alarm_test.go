@@ -0,0 +1,99 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
+)
+
+func TestCalendarAlarms(t *testing.T) {
+	repeat := 1
+	cal := Create("Alarms", "Calendar with reminders")
+	cal.Events = []Event{{
+		Title:     "Weekly Sync",
+		StartDate: time.Date(2025, time.November, 17, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.December, 8, 23, 0, 0, 0, time.UTC),
+		TimeZone:  TimeZone(timezones.UTC),
+		Recurrences: []Recurrences{{
+			Frequency:  WeeklyFrequency,
+			Day:        time.Monday,
+			StartTime:  time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:    time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+			Exceptions: []time.Time{time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+		Reminders: []Reminder{
+			{Action: DisplayReminderAction, Description: "Soon", Trigger: -15 * time.Minute, Repeat: &repeat, RepeatInterval: 5 * time.Minute},
+			{Action: DisplayReminderAction, Description: "Wrap up", Trigger: -10 * time.Minute, TriggerRelation: EndTriggerRelation},
+		},
+	}}
+	due := time.Date(2025, time.November, 20, 17, 0, 0, 0, time.UTC)
+	cal.Todos = []Todo{{
+		Summary:   "Report",
+		Due:       &due,
+		Reminders: []Reminder{{Action: DisplayReminderAction, Description: "Due", Trigger: -time.Hour, TriggerRelation: EndTriggerRelation}},
+	}}
+
+	alarms := cal.Alarms(time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	expected := []struct {
+		fire        time.Time
+		description string
+	}{
+		{time.Date(2025, time.November, 17, 8, 45, 0, 0, time.UTC), "Soon"},
+		{time.Date(2025, time.November, 17, 8, 50, 0, 0, time.UTC), "Soon"},
+		{time.Date(2025, time.November, 17, 9, 50, 0, 0, time.UTC), "Wrap up"},
+		{time.Date(2025, time.November, 20, 16, 0, 0, 0, time.UTC), "Due"},
+		{time.Date(2025, time.November, 24, 8, 45, 0, 0, time.UTC), "Soon"},
+		{time.Date(2025, time.November, 24, 8, 50, 0, 0, time.UTC), "Soon"},
+		{time.Date(2025, time.November, 24, 9, 50, 0, 0, time.UTC), "Wrap up"},
+		{time.Date(2025, time.December, 8, 8, 45, 0, 0, time.UTC), "Soon"},
+		{time.Date(2025, time.December, 8, 8, 50, 0, 0, time.UTC), "Soon"},
+		{time.Date(2025, time.December, 8, 9, 50, 0, 0, time.UTC), "Wrap up"},
+	}
+	if len(alarms) != len(expected) {
+		t.Fatalf("Alarms() returned %d alarms, want %d: %+v", len(alarms), len(expected), alarms)
+	}
+	for i, want := range expected {
+		if !alarms[i].FireTime.Equal(want.fire) || alarms[i].Reminder.Description != want.description {
+			t.Errorf("alarm %d = %v %q, want %v %q", i, alarms[i].FireTime, alarms[i].Reminder.Description, want.fire, want.description)
+		}
+	}
+	if _, ok := alarms[3].Component.(*Todo); !ok {
+		t.Errorf("Expected alarm 3 to belong to a *Todo, got %T", alarms[3].Component)
+	}
+	if !alarms[4].Occurrence.Equal(time.Date(2025, time.November, 24, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("alarm 4 occurrence = %v, want 2025-11-24 09:00", alarms[4].Occurrence)
+	}
+
+	window := cal.Alarms(time.Date(2025, time.November, 24, 0, 0, 0, 0, time.UTC), time.Date(2025, time.November, 24, 8, 50, 0, 0, time.UTC))
+	if len(window) != 1 || !window[0].FireTime.Equal(time.Date(2025, time.November, 24, 8, 45, 0, 0, time.UTC)) {
+		t.Errorf("Alarms() in window = %+v, want only 2025-11-24 08:45", window)
+	}
+}
+
+func TestCalendarAlarmsAbsoluteAndAcknowledged(t *testing.T) {
+	start := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	at := start.Add(-24 * time.Hour)
+	acknowledged := start.Add(-10 * time.Minute)
+	cal := Create("Alarms", "Calendar with reminders")
+	cal.Events = []Event{{
+		Title:     "Review",
+		StartDate: start,
+		EndDate:   start.Add(time.Hour),
+		TimeZone:  TimeZone(timezones.UTC),
+		Reminders: []Reminder{
+			{Action: DisplayReminderAction, Description: "Day before", TriggerAt: &at},
+			{Action: DisplayReminderAction, Description: "Dismissed", Trigger: -15 * time.Minute, Acknowledged: &acknowledged},
+			{Action: DisplayReminderAction, Description: "At start"},
+		},
+	}}
+
+	alarms := cal.Alarms(start.Add(-48*time.Hour), start.Add(48*time.Hour))
+	if len(alarms) != 2 {
+		t.Fatalf("Alarms() returned %d alarms, want 2: %+v", len(alarms), alarms)
+	}
+	if !alarms[0].FireTime.Equal(at) || !alarms[1].FireTime.Equal(start) {
+		t.Errorf("Alarms() = %v, %v, want %v, %v", alarms[0].FireTime, alarms[1].FireTime, at, start)
+	}
+}
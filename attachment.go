@@ -0,0 +1,103 @@
+package ical
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// iCalendar ATTACH property
+//
+// Either URI or Data must be set, but not both.
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-8-1-1-attachment.html
+type Attachment struct {
+	// OPTIONAL: Media type of the attachment
+	//
+	// E.g., "application/pdf", "image/png", "audio/basic"
+	FormatType string
+
+	// OPTIONAL: Location of the attachment
+	//
+	// E.g., "https://example.com/agenda.pdf"
+	URI string
+
+	// OPTIONAL: Inline binary content of the attachment
+	//
+	// Written as base64 with ENCODING=BASE64;VALUE=BINARY
+	Data []byte
+}
+
+// Generate the iCalendar ATTACH property
+func (a *Attachment) generate(builder *strings.Builder) error {
+	if !a.valid() {
+		return ErrInvalidAttachment
+	}
+
+	line := "ATTACH"
+	if a.FormatType != "" {
+		line += ";FMTTYPE=" + paramValue(a.FormatType)
+	}
+	if a.Data != nil {
+		line += ";ENCODING=BASE64;VALUE=BINARY:" + base64.StdEncoding.EncodeToString(a.Data)
+	} else {
+		line += ":" + a.URI
+	}
+
+	builder.WriteString(foldLine(line) + lineBreak)
+	return nil
+}
+
+func (a *Attachment) valid() bool {
+	return len(a.validationErrors()) == 0
+}
+
+func (a *Attachment) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidAttachment}
+	v.check(a.URI != "" || a.Data != nil, "URI", "is required without Data", nil)
+	v.check(a.URI == "" || a.Data == nil, "Data", "can not be used with URI", nil)
+	v.check(a.URI == "" || strings.Contains(a.URI, ":"), "URI", "must be a URI", a.URI)
+	return v.errs
+}
+
+// ParseAttachment decodes an ATTACH content line, which may still be folded.
+//
+// Inline attachments are decoded from base64 back into Data.
+func ParseAttachment(line string) (*Attachment, error) {
+	lines := unfoldLines(line)
+	if len(lines) != 1 {
+		return nil, ErrInvalidAttachment
+	}
+	cl, err := parseContentLine(lines[0])
+	if err != nil || cl.Name != "ATTACH" {
+		return nil, ErrInvalidAttachment
+	}
+	return attachmentFromContentLine(cl)
+}
+
+func attachmentFromContentLine(cl contentLine) (*Attachment, error) {
+	a := &Attachment{}
+	a.FormatType, _ = cl.param("FMTTYPE")
+
+	encoding, _ := cl.param("ENCODING")
+	value, _ := cl.param("VALUE")
+	switch {
+	case strings.EqualFold(encoding, "BASE64"):
+		if value != "" && !strings.EqualFold(value, "BINARY") {
+			return nil, ErrInvalidAttachment
+		}
+		data, err := base64.StdEncoding.DecodeString(cl.Value)
+		if err != nil {
+			return nil, ErrInvalidAttachment
+		}
+		a.Data = data
+	case encoding != "":
+		return nil, ErrInvalidAttachment
+	default:
+		a.URI = cl.Value
+	}
+
+	if !a.valid() {
+		return nil, ErrInvalidAttachment
+	}
+	return a, nil
+}
@@ -0,0 +1,153 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateAttachment(t *testing.T) {
+	var tests = []struct {
+		name       string
+		attachment Attachment
+		expected   string
+	}{
+		{"uri", Attachment{URI: "https://example.com/agenda.pdf", FormatType: "application/pdf"}, "ATTACH;FMTTYPE=application/pdf:https://example.com/agenda.pdf\r\n"},
+		{"uri without type", Attachment{URI: "ftp://example.com/pub/sound.wav"}, "ATTACH:ftp://example.com/pub/sound.wav\r\n"},
+		{"binary", Attachment{Data: []byte("hello"), FormatType: "text/plain"}, "ATTACH;FMTTYPE=text/plain;ENCODING=BASE64;VALUE=BINARY:aGVsbG8=\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var builder strings.Builder
+			err := tt.attachment.generate(&builder)
+			if err != nil {
+				t.Fatalf("generate() returned error: %v", err)
+			}
+			if builder.String() != tt.expected {
+				t.Errorf("generate() = %q, want %q", builder.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateAttachmentFolding(t *testing.T) {
+	data := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 100)
+	attachment := Attachment{Data: data, FormatType: "image/png"}
+
+	var builder strings.Builder
+	err := attachment.generate(&builder)
+	if err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(builder.String(), lineBreak), lineBreak) {
+		if len(line) > maxLineOctets {
+			t.Errorf("Line exceeds %d octets: %q", maxLineOctets, line)
+		}
+	}
+
+	parsed, err := ParseAttachment(builder.String())
+	if err != nil {
+		t.Fatalf("ParseAttachment() returned error: %v", err)
+	}
+	if !bytes.Equal(parsed.Data, data) {
+		t.Errorf("ParseAttachment() did not round trip inline data")
+	}
+	if parsed.FormatType != "image/png" {
+		t.Errorf("ParseAttachment() FormatType = %q, want %q", parsed.FormatType, "image/png")
+	}
+}
+
+func TestParseAttachment(t *testing.T) {
+	var tests = []struct {
+		line      string
+		expected  Attachment
+		expectErr bool
+	}{
+		{"ATTACH:CID:jsmith.part3.960817T083000.xyzMail@example.com", Attachment{URI: "CID:jsmith.part3.960817T083000.xyzMail@example.com"}, false},
+		{"ATTACH;FMTTYPE=application/postscript:ftp://example.com/pub/\r\n reports/r-960812.ps", Attachment{URI: "ftp://example.com/pub/reports/r-960812.ps", FormatType: "application/postscript"}, false},
+		{"ATTACH;FMTTYPE=text/plain;ENCODING=BASE64;VALUE=BINARY:aGVsbG8=", Attachment{Data: []byte("hello"), FormatType: "text/plain"}, false},
+		{"ATTACH;ENCODING=BASE64;VALUE=BINARY:not base64!", Attachment{}, true},
+		{"ATTACH;ENCODING=8BIT:hello", Attachment{}, true},
+		{"DESCRIPTION:hello", Attachment{}, true},
+		{"ATTACH:no-scheme", Attachment{}, true},
+	}
+
+	for _, tt := range tests {
+		a, err := ParseAttachment(tt.line)
+		if tt.expectErr {
+			if err == nil {
+				t.Errorf("ParseAttachment(%q) expected error, got %+v", tt.line, a)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAttachment(%q) returned error: %v", tt.line, err)
+			continue
+		}
+		if a.URI != tt.expected.URI || a.FormatType != tt.expected.FormatType || !bytes.Equal(a.Data, tt.expected.Data) {
+			t.Errorf("ParseAttachment(%q) = %+v, want %+v", tt.line, a, tt.expected)
+		}
+	}
+}
+
+func TestAttachmentValid(t *testing.T) {
+	var tests = []struct {
+		attachment Attachment
+		expected   bool
+	}{
+		{Attachment{URI: "https://example.com/a.pdf"}, true},
+		{Attachment{Data: []byte{1, 2, 3}}, true},
+		{Attachment{}, false},
+		{Attachment{URI: "https://example.com/a.pdf", Data: []byte{1}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.attachment.valid(); got != tt.expected {
+			t.Errorf("Attachment.valid() = %v, want %v for %+v", got, tt.expected, tt.attachment)
+		}
+	}
+}
+
+func TestReminderAttachments(t *testing.T) {
+	sound := Attachment{URI: "https://example.com/ding.wav", FormatType: "audio/wav"}
+	var tests = []struct {
+		name     string
+		reminder Reminder
+		expected bool
+	}{
+		{"audio with sound", Reminder{Action: AudioReminderAction, Description: "Ding", Trigger: -time.Minute, Attachments: []Attachment{sound}}, true},
+		{"audio with two sounds", Reminder{Action: AudioReminderAction, Description: "Ding", Trigger: -time.Minute, Attachments: []Attachment{sound, sound}}, false},
+		{"display with attachment", Reminder{Action: DisplayReminderAction, Description: "Ding", Trigger: -time.Minute, Attachments: []Attachment{sound}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reminder.valid(); got != tt.expected {
+				t.Errorf("Reminder.valid() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEventAddAttachment(t *testing.T) {
+	event := mockEvent()
+	err := event.AddAttachment(Attachment{URI: "https://example.com/agenda.pdf", FormatType: "application/pdf"})
+	if err != nil {
+		t.Fatalf("AddAttachment() returned error: %v", err)
+	}
+	err = event.AddAttachment(Attachment{})
+	if err == nil {
+		t.Errorf("AddAttachment() expected error for empty attachment")
+	}
+
+	output, err := event.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if !strings.Contains(output, "ATTACH;FMTTYPE=application/pdf:https://example.com/agenda.pdf") {
+		t.Errorf("Generated event missing ATTACH property: %s", output)
+	}
+}
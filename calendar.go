@@ -1,9 +1,13 @@
 package ical
 
 import (
+	"errors"
 	"os"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
 )
 
 const (
@@ -11,6 +15,14 @@ const (
 	iCalTimeLayout = "20060102T150405"
 )
 
+// Properties of every generated VCALENDAR
+var calendarProperties = []string{
+	"VERSION:2.0",
+	"PRODID:-//TylerChristensen100//iCal_Generator//EN",
+	"CALSCALE:GREGORIAN",
+	"METHOD:PUBLISH",
+}
+
 // Root VCALENDAR structure
 //
 // Events, Journals, and Todos are not required, but at least one must be present
@@ -28,32 +40,67 @@ type Calendar struct {
 	Journals []Journal
 	// OPTIONAL: List of todos in the calendar
 	Todos []Todo
+
+	// OPTIONAL: Write TZIDs as Windows time zone names, e.g. "Eastern Standard Time",
+	// for calendars imported into Outlook or Exchange
+	//
+	// Time zones without a Windows name keep their IANA name
+	WindowsTimeZoneNames bool
+
+	// What the calendar was read from by Parse, nil for calendars created in code
+	source *source
+}
+
+// Options that change how components are written
+type generateOptions struct {
+	windowsTimeZoneNames bool
+}
+
+// The TZID written for the time zone
+func (o generateOptions) tzid(tz TimeZone) string {
+	if o.windowsTimeZoneNames {
+		if name, found := tz.WindowsName(); found {
+			return name
+		}
+	}
+	return tz.ID()
 }
 
 func (c *Calendar) AddEvent(e Event) error {
-	if !e.Valid() {
-		return ErrInvalidEvent
+	if err := e.Validate(); err != nil {
+		return err
 	}
 	c.Events = append(c.Events, e)
 	return nil
 }
 
 func (c *Calendar) AddJournal(j Journal) error {
-	if !j.valid() {
-		return ErrInvalidJournal
+	if err := j.Validate(); err != nil {
+		return err
 	}
 	c.Journals = append(c.Journals, j)
 	return nil
 }
 
 func (c *Calendar) AddTodo(t Todo) error {
-	if !t.valid() {
-		return ErrInvalidTodo
+	if err := t.Validate(); err != nil {
+		return err
 	}
 	c.Todos = append(c.Todos, t)
 	return nil
 }
 
+// EventsByCategory returns the events in the given category, ignoring case.
+func (c *Calendar) EventsByCategory(category string) []Event {
+	var events []Event
+	for _, event := range c.Events {
+		if event.HasCategory(category) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
 // Saves the calendar to a file at the specified path.
 func (c *Calendar) Save(path string) error {
 	icalData, err := c.Generate()
@@ -74,35 +121,41 @@ func (c *Calendar) Save(path string) error {
 
 // Generate creates the iCal formatted string for the entire calendar.
 func (c *Calendar) Generate() ([]byte, error) {
-	if !c.Valid() {
-		return nil, ErrInvalidCalendar
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := generateOptions{windowsTimeZoneNames: c.WindowsTimeZoneNames}
+	if c.source != nil {
+		return c.generateFromSource(opts)
 	}
 
 	var builder strings.Builder
 	builder.WriteString("BEGIN:VCALENDAR" + lineBreak)
-	builder.WriteString("VERSION:2.0" + lineBreak)
-	builder.WriteString("PRODID:-//TylerChristensen100//iCal_Generator//EN" + lineBreak)
-	builder.WriteString("CALSCALE:GREGORIAN" + lineBreak)
-	builder.WriteString("METHOD:PUBLISH" + lineBreak)
+	for _, property := range calendarProperties {
+		builder.WriteString(property + lineBreak)
+	}
 
-	c.generateTimeZones(&builder)
+	if err := c.generateTimeZones(&builder, opts); err != nil {
+		return nil, err
+	}
 
 	for _, event := range c.Events {
-		event, err := event.Generate()
+		event, err := event.generate(opts)
 		if err != nil {
 			return nil, err
 		}
 		builder.WriteString(event)
 	}
 	for _, journal := range c.Journals {
-		err := journal.generate(&builder)
+		err := journal.generate(&builder, opts)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	for _, todo := range c.Todos {
-		err := todo.generate(&builder)
+		err := todo.generate(&builder, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -112,44 +165,112 @@ func (c *Calendar) Generate() ([]byte, error) {
 	return []byte(builder.String()), nil
 }
 
-func (c *Calendar) generateTimeZones(builder *strings.Builder) {
-	uniqueTimeZones := make(map[TimeZone]bool)
-	for _, event := range c.Events {
-		uniqueTimeZones[event.TimeZone] = true
+// Writes a VTIMEZONE for every time zone referenced by a TZID, truncated to the dates it is used on.
+//
+// Time zones are written in order of their ID.
+func (c *Calendar) generateTimeZones(builder *strings.Builder, opts generateOptions) error {
+	return c.generateTimeZonesFor(builder, opts, func(string) bool { return true })
+}
+
+// Writes a VTIMEZONE for the time zones whose TZID is needed
+//
+// Time zones without a definition are skipped, a time zone source that failed to load is an error.
+func (c *Calendar) generateTimeZonesFor(builder *strings.Builder, opts generateOptions, needed func(tzid string) bool) error {
+	zoned := c.zonedTimes()
+	timeZones := make([]TimeZone, 0, len(zoned))
+	for timeZone := range zoned {
+		timeZones = append(timeZones, timeZone)
 	}
-	for timeZone := range uniqueTimeZones {
-		data, found := timeZone.iCal()
-		if found {
-			builder.WriteString(data)
+	sort.Slice(timeZones, func(i, j int) bool {
+		return timeZones[i] < timeZones[j]
+	})
+
+	for _, timeZone := range timeZones {
+		times := zoned[timeZone]
+		from, to := times[0], times[0]
+		for _, t := range times[1:] {
+			if t.Before(from) {
+				from = t
+			}
+			if t.After(to) {
+				to = t
+			}
+		}
+
+		tzid := opts.tzid(timeZone)
+		if !needed(tzid) {
+			continue
+		}
+		data, err := timeZone.definition(from, to)
+		if errors.Is(err, ErrInvalidTimeZone) || errors.Is(err, timezones.ErrUnknownTimeZone) {
+			continue
+		}
+		if err != nil {
+			return err
 		}
+		if tzid != timeZone.ID() {
+			data = strings.Replace(data, "TZID:"+timeZone.ID()+lineBreak, "TZID:"+tzid+lineBreak, 1)
+		}
+		builder.WriteString(data)
 	}
+	return nil
 }
 
-func (c *Calendar) Valid() bool {
-	if c.Name == "" {
-		return false
-	}
+// Every time zone written as a TZID, with the times written in it
+func (c *Calendar) zonedTimes() map[TimeZone][]time.Time {
+	zoned := make(map[TimeZone][]time.Time)
 	for _, event := range c.Events {
-		if !event.Valid() {
-			return false
+		zoned[event.TimeZone] = append(zoned[event.TimeZone], event.StartDate, event.End())
+		if !event.EndDate.IsZero() {
+			zoned[event.TimeZone] = append(zoned[event.TimeZone], event.EndDate)
 		}
 	}
-	for _, journal := range c.Journals {
-		if !journal.valid() {
-			return false
+	for _, todo := range c.Todos {
+		if todo.TimeZone == "" {
+			continue
+		}
+		for _, t := range []*time.Time{todo.StartDate, todo.Due} {
+			if t != nil {
+				zoned[todo.TimeZone] = append(zoned[todo.TimeZone], *t)
+			}
 		}
 	}
-	for _, todo := range c.Todos {
-		if !todo.valid() {
-			return false
+	for _, journal := range c.Journals {
+		if journal.TimeZone != "" && journal.StartDate != nil {
+			zoned[journal.TimeZone] = append(zoned[journal.TimeZone], *journal.StartDate)
+			zoned[journal.TimeZone] = append(zoned[journal.TimeZone], journal.RecurrenceDates...)
 		}
 	}
+	return zoned
+}
 
-	// If there is nothing in the calendar, it's invalid
-	if len(c.Events) == 0 && len(c.Journals) == 0 && len(c.Todos) == 0 {
-		return false
+func (c *Calendar) Valid() bool {
+	return len(c.validationErrors()) == 0
+}
+
+// Validate explains why the calendar is not valid, with a ValidationError for every broken rule
+// of the calendar and its components.
+func (c *Calendar) Validate() error {
+	return c.validationErrors().err()
+}
+
+func (c *Calendar) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidCalendar}
+	// Calendar data may have no name, calendars read from it are generated without one as they were read
+	v.check(c.Name != "" || c.source != nil, "Name", "is required", nil)
+	for i := range c.Events {
+		v.nest(indexPath("Events", i), c.Events[i].validationErrors())
+	}
+	for i := range c.Journals {
+		v.nest(indexPath("Journals", i), c.Journals[i].validationErrors())
 	}
-	return true
+	for i := range c.Todos {
+		v.nest(indexPath("Todos", i), c.Todos[i].validationErrors())
+	}
+
+	// If there is nothing in the calendar, it's invalid
+	v.check(len(c.Events) > 0 || len(c.Journals) > 0 || len(c.Todos) > 0 || c.source != nil, "", "needs an event, journal entry or To-Do", nil)
+	return v.errs
 }
 
 // ListConflicts returns a list of events that have scheduling conflicts with other events in the calendar.
@@ -83,25 +83,78 @@ func TestGenerateTimeZones(t *testing.T) {
 	validtz := TimeZone(timezones.US_Central)
 
 	cal := mockCalendar(validtz)
-	cal.generateTimeZones(&builder)
+	cal.generateTimeZones(&builder, generateOptions{})
 	result := builder.String()
-	expectedTimeZones, found := timezones.Get(timezones.US_Central)
-	if !found || !strings.Contains(result, string(expectedTimeZones)) {
+	expectedTimeZones, err := timezones.Generate(timezones.US_Central, cal.Events[0].StartDate, cal.Events[0].EndDate)
+	if err != nil || !strings.Contains(result, expectedTimeZones) {
 		t.Errorf("Expected time zone definition not found in generated iCal data")
 	}
 	builder.Reset()
 
 	invalidtz := TimeZone("Invalid/Timezone")
 	cal = mockCalendar(invalidtz)
-	cal.generateTimeZones(&builder)
+	cal.generateTimeZones(&builder, generateOptions{})
 	result = builder.String()
-	expectedTimeZones, found = timezones.Get(timezones.UTC)
-	if !found && !strings.Contains(result, string(expectedTimeZones)) {
+	expectedTimeZones, err = timezones.Get(timezones.UTC)
+	if err != nil && !strings.Contains(result, string(expectedTimeZones)) {
 		t.Errorf("Expected UTC time zone definition not found in generated iCal data for invalid timezone")
 	}
 
 }
 
+func TestGenerateWindowsTimeZoneNames(t *testing.T) {
+	cal := mockCalendar(TimeZone(timezones.America_New_York))
+	cal.WindowsTimeZoneNames = true
+
+	data, err := cal.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	output := string(data)
+	for _, expected := range []string{
+		"TZID:Eastern Standard Time\r\n",
+		"DTSTART;TZID=Eastern Standard Time:",
+		"DTEND;TZID=Eastern Standard Time:",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generated calendar missing %q", expected)
+		}
+	}
+	if strings.Contains(output, "America/New_York") {
+		t.Errorf("Generated calendar should not contain IANA names: %s", output)
+	}
+}
+
+func TestGenerateTimeZonesForTodosAndJournals(t *testing.T) {
+	due := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	start := time.Date(2025, time.February, 1, 9, 0, 0, 0, time.UTC)
+
+	cal := Create("Todo Calendar", "A calendar with only todos and journals")
+	cal.AddTodo(Todo{Summary: "Zoned Todo", Due: &due, TimeZone: TimeZone(timezones.Europe_Paris)})
+	cal.AddTodo(Todo{Summary: "Floating Todo", Due: &due})
+	cal.AddJournal(Journal{Summary: "Zoned Journal", Description: "Notes", StartDate: &start, TimeZone: TimeZone(timezones.America_Chicago)})
+	cal.AddJournal(Journal{Summary: "Unreferenced Zone", Description: "No start", TimeZone: TimeZone(timezones.Asia_Tokyo)})
+
+	var builder strings.Builder
+	cal.generateTimeZones(&builder, generateOptions{})
+	result := builder.String()
+
+	chicago := strings.Index(result, "TZID:America/Chicago\r\n")
+	paris := strings.Index(result, "TZID:Europe/Paris\r\n")
+	if chicago < 0 || paris < 0 {
+		t.Fatalf("Expected VTIMEZONEs for the todo and journal zones, got %s", result)
+	}
+	if chicago > paris {
+		t.Errorf("Expected VTIMEZONEs sorted by TZID, got %s", result)
+	}
+	if strings.Contains(result, "Asia/Tokyo") {
+		t.Errorf("Expected no VTIMEZONE for a zone that is not referenced, got %s", result)
+	}
+	if strings.Count(result, "BEGIN:VTIMEZONE") != 2 {
+		t.Errorf("Expected 2 VTIMEZONEs, got %s", result)
+	}
+}
+
 func TestSave(t *testing.T) {
 	const fileName = "./test/tmp/test_calendar.ics"
 	cal := mockCalendar()
@@ -162,6 +215,26 @@ func TestGenerateCalendarWithTodoOnly(t *testing.T) {
 	}
 }
 
+func TestEventsByCategory(t *testing.T) {
+	cal := mockCalendar()
+	event := mockEvent()
+	event.Title = "Public Talk"
+	event.Categories = []string{"PUBLIC", "TALK"}
+	err := cal.AddEvent(event)
+	if err != nil {
+		t.Fatalf("AddEvent() returned error: %v", err)
+	}
+
+	events := cal.EventsByCategory("public")
+	if len(events) != 1 || events[0].Title != "Public Talk" {
+		t.Errorf("EventsByCategory(\"public\") = %v, want [Public Talk]", events)
+	}
+
+	if events := cal.EventsByCategory("holiday"); len(events) != 0 {
+		t.Errorf("EventsByCategory(\"holiday\") = %v, want none", events)
+	}
+}
+
 func mockCalendar(tz ...TimeZone) *Calendar {
 	var zone TimeZone = TimeZone(timezones.US_Eastern)
 	if tz != nil {
@@ -0,0 +1,97 @@
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Component is a calendar component: *Event, *Todo or *Journal.
+type Component interface {
+	uid() string
+	relations() []Relation
+	sequence() int
+	lastModified() *time.Time
+}
+
+func (e *Event) relations() []Relation {
+	return e.Relations
+}
+
+func (t *Todo) relations() []Relation {
+	return t.Relations
+}
+
+func (j *Journal) relations() []Relation {
+	return j.Relations
+}
+
+// Every component in the calendar, in the order events, todos, journals
+func (c *Calendar) components() []Component {
+	components := make([]Component, 0, len(c.Events)+len(c.Todos)+len(c.Journals))
+	for i := range c.Events {
+		components = append(components, &c.Events[i])
+	}
+	for i := range c.Todos {
+		components = append(components, &c.Todos[i])
+	}
+	for i := range c.Journals {
+		components = append(components, &c.Journals[i])
+	}
+	return components
+}
+
+func (e *Event) sequence() int {
+	return e.Sequence
+}
+
+func (t *Todo) sequence() int {
+	return t.Sequence
+}
+
+func (j *Journal) sequence() int {
+	return j.Sequence
+}
+
+func (e *Event) lastModified() *time.Time {
+	return e.LastModified
+}
+
+func (t *Todo) lastModified() *time.Time {
+	return t.LastModified
+}
+
+func (j *Journal) lastModified() *time.Time {
+	return j.LastModified
+}
+
+// The RECURRENCE-ID of a component read by Parse that overrides a single occurrence,
+// empty for every other component
+func recurrenceID(c Component) string {
+	var s *source
+	switch component := c.(type) {
+	case *Event:
+		s = component.source
+	case *Todo:
+		s = component.source
+	case *Journal:
+		s = component.source
+	}
+	if s == nil {
+		return ""
+	}
+	if prop, found := s.raw.property("RECURRENCE-ID"); found {
+		return prop.Value
+	}
+	return ""
+}
+
+// Writes the SEQUENCE and LAST-MODIFIED of a component
+func generateRevision(builder *strings.Builder, sequence int, lastModified *time.Time) {
+	if sequence != 0 {
+		builder.WriteString("SEQUENCE:" + strconv.Itoa(sequence) + lineBreak)
+	}
+	if lastModified != nil {
+		builder.WriteString("LAST-MODIFIED:" + timeToICal(lastModified.UTC()) + "Z" + lineBreak)
+	}
+}
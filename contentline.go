@@ -0,0 +1,146 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lines of text SHOULD NOT be longer than 75 octets, excluding the line break.
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-1-content-lines.html
+const maxLineOctets = 75
+
+// A single unfolded iCalendar content line
+//
+// name *(";" param ) ":" value
+type contentLine struct {
+	Name   string
+	Params []contentParam
+	Value  string
+}
+
+type contentParam struct {
+	Name   string
+	Values []string
+}
+
+// Returns the first value of the named parameter
+func (l *contentLine) param(name string) (string, bool) {
+	for _, p := range l.Params {
+		if strings.EqualFold(p.Name, name) && len(p.Values) > 0 {
+			return p.Values[0], true
+		}
+	}
+	return "", false
+}
+
+// Returns every value of the named parameter
+func (l *contentLine) params(name string) []string {
+	var values []string
+	for _, p := range l.Params {
+		if strings.EqualFold(p.Name, name) {
+			values = append(values, p.Values...)
+		}
+	}
+	return values
+}
+
+// foldLine splits a content line into 75 octet chunks, each continuation
+// line starting with a single space. Multi-octet UTF-8 sequences are never split.
+func foldLine(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var builder strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		builder.WriteString(line[:cut])
+		builder.WriteString(lineBreak + " ")
+		line = line[cut:]
+		// The leading space of a continuation line counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	builder.WriteString(line)
+	return builder.String()
+}
+
+// unfoldLines joins folded continuation lines and splits the data into content lines.
+func unfoldLines(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	var lines []string
+	for _, raw := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		if raw == "" {
+			continue
+		}
+		lines = append(lines, raw)
+	}
+	return lines
+}
+
+// parseContentLine splits an unfolded content line into its name, parameters and value.
+func parseContentLine(line string) (contentLine, error) {
+	var cl contentLine
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return cl, fmt.Errorf("content line %q: missing property name", line)
+	}
+	cl.Name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		line = line[i+1:]
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return cl, fmt.Errorf("content line %q: malformed parameter", cl.Name)
+		}
+		param := contentParam{Name: strings.ToUpper(line[:eq])}
+		line = line[eq+1:]
+
+		for {
+			var value string
+			if strings.HasPrefix(line, `"`) {
+				end := strings.IndexByte(line[1:], '"')
+				if end < 0 {
+					return cl, fmt.Errorf("content line %q: unterminated quoted parameter", cl.Name)
+				}
+				value = line[1 : end+1]
+				line = line[end+2:]
+			} else {
+				end := strings.IndexAny(line, ",;:")
+				if end < 0 {
+					return cl, fmt.Errorf("content line %q: missing value", cl.Name)
+				}
+				value = line[:end]
+				line = line[end:]
+			}
+			param.Values = append(param.Values, value)
+
+			if !strings.HasPrefix(line, ",") {
+				break
+			}
+			line = line[1:]
+		}
+		cl.Params = append(cl.Params, param)
+
+		if line == "" {
+			return cl, fmt.Errorf("content line %q: missing value", cl.Name)
+		}
+		i = 0
+		if line[0] != ';' && line[0] != ':' {
+			return cl, fmt.Errorf("content line %q: malformed parameter", cl.Name)
+		}
+	}
+
+	cl.Value = line[i+1:]
+	return cl, nil
+}
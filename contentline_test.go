@@ -0,0 +1,57 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFoldLine(t *testing.T) {
+	short := "SUMMARY:Short"
+	if foldLine(short) != short {
+		t.Errorf("foldLine() changed a short line: %q", foldLine(short))
+	}
+
+	long := "DESCRIPTION:" + strings.Repeat("é", 100)
+	folded := foldLine(long)
+	for _, line := range strings.Split(folded, lineBreak) {
+		if len(line) > maxLineOctets {
+			t.Errorf("Folded line exceeds %d octets: %q", maxLineOctets, line)
+		}
+		if !strings.HasPrefix(line, "DESCRIPTION:") && !strings.HasPrefix(line, " é") {
+			t.Errorf("Folded line split a multi-octet character: %q", line)
+		}
+	}
+
+	unfolded := unfoldLines(folded)
+	if len(unfolded) != 1 || unfolded[0] != long {
+		t.Errorf("unfoldLines() did not restore the original line: %q", unfolded)
+	}
+}
+
+func TestParseContentLine(t *testing.T) {
+	cl, err := parseContentLine(`attendee;CN="Doe, John";ROLE=REQ-PARTICIPANT;DELEGATED-TO="mailto:a@example.com","mailto:b@example.com":mailto:john@example.com`)
+	if err != nil {
+		t.Fatalf("parseContentLine() returned error: %v", err)
+	}
+	if cl.Name != "ATTENDEE" {
+		t.Errorf("Name = %q, want ATTENDEE", cl.Name)
+	}
+	if cn, _ := cl.param("cn"); cn != "Doe, John" {
+		t.Errorf("CN = %q, want %q", cn, "Doe, John")
+	}
+	if len(cl.Params) != 3 || len(cl.Params[2].Values) != 2 {
+		t.Errorf("Unexpected parameters: %+v", cl.Params)
+	}
+	if delegates := cl.params("delegated-to"); len(delegates) != 2 || delegates[1] != "mailto:b@example.com" {
+		t.Errorf("DELEGATED-TO = %q, want both addresses", delegates)
+	}
+	if cl.Value != "mailto:john@example.com" {
+		t.Errorf("Value = %q, want %q", cl.Value, "mailto:john@example.com")
+	}
+
+	for _, bad := range []string{":value", "NAME", "NAME;PARAM:value", `NAME;PARAM="open:value`} {
+		if _, err := parseContentLine(bad); err == nil {
+			t.Errorf("parseContentLine(%q) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,806 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
+)
+
+// Recurring events without UNTIL or COUNT are read as ending this many years after they start,
+// as events need an end date
+const unboundedRecurrenceYears = 1
+
+// Layouts ParseLenient tries for date-times that are not in the iCalendar format
+var lenientTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"20060102T1504",
+	"20060102T150405.000",
+}
+
+// Layouts ParseLenient tries for dates that are not in the iCalendar format
+var lenientDateLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// Reads the VCALENDAR components into one Calendar
+func (p *parser) decodeCalendar(calendars []*rawComponent) (*Calendar, error) {
+	cal := &Calendar{source: &source{raw: calendars[0]}}
+	if len(calendars) > 1 {
+		// Calendars after the first add their components, as when they are generated again
+		merged := *calendars[0]
+		merged.Components = nil
+		for _, component := range calendars {
+			merged.Components = append(merged.Components, component.Components...)
+		}
+		cal.source.raw = &merged
+	}
+	for _, component := range calendars {
+		for i := range component.Properties {
+			prop := &component.Properties[i]
+			switch prop.Name {
+			case "NAME", "X-WR-CALNAME":
+				if cal.Name == "" {
+					cal.Name = p.text(prop)
+				}
+			case "DESCRIPTION", "X-WR-CALDESC":
+				if cal.Description == "" {
+					cal.Description = p.text(prop)
+				}
+			}
+		}
+
+		for _, child := range component.Components {
+			switch child.Name {
+			case "VEVENT":
+				event := p.decodeEvent(child)
+				// Set first, so the baseline is validated as an event that was read
+				event.source = &source{raw: child}
+				event.source = newSource(child, func() (string, error) {
+					return event.generate(generateOptions{})
+				})
+				cal.Events = append(cal.Events, event)
+			case "VTODO":
+				todo := p.decodeTodo(child)
+				todo.source = newSource(child, func() (string, error) {
+					var builder strings.Builder
+					err := todo.generate(&builder, generateOptions{})
+					return builder.String(), err
+				})
+				cal.Todos = append(cal.Todos, todo)
+			case "VJOURNAL":
+				journal := p.decodeJournal(child)
+				journal.source = newSource(child, func() (string, error) {
+					var builder strings.Builder
+					err := journal.generate(&builder, generateOptions{})
+					return builder.String(), err
+				})
+				cal.Journals = append(cal.Journals, journal)
+			}
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+	}
+	cal.source.baseline = cal.generateProperties()
+	return cal, nil
+}
+
+// Reads a VEVENT
+//
+// Floating times and dates are read as UTC, as events need a time zone.
+func (p *parser) decodeEvent(c *rawComponent) Event {
+	var e Event
+	var start, end parsedTime
+	var hasStart, hasEnd bool
+	var duration *Duration
+	var rule *recurrenceRule
+	var exceptions []time.Time
+
+	for i := range c.Properties {
+		prop := &c.Properties[i]
+		switch prop.Name {
+		case "UID":
+			e.UID = prop.Value
+		case "SEQUENCE":
+			if sequence, ok := p.integer(prop); ok {
+				e.Sequence = sequence
+			}
+		case "LAST-MODIFIED":
+			if modified, ok := p.dateTime(prop); ok {
+				e.LastModified = &modified.time
+			}
+		case "SUMMARY":
+			e.Title = p.text(prop)
+		case "DESCRIPTION":
+			e.Description = p.text(prop)
+		case "LOCATION":
+			e.Location = p.text(prop)
+		case "ORGANIZER":
+			organizer := participantFromContentLine(prop.contentLine)
+			e.Organizer = &organizer
+		case "DTSTART":
+			start, hasStart = p.dateTime(prop)
+		case "DTEND":
+			end, hasEnd = p.dateTime(prop)
+		case "DURATION":
+			if d, ok := p.duration(prop); ok {
+				duration = &d
+			}
+		case "RRULE":
+			rule = p.recurrenceRule(prop)
+		case "EXDATE":
+			for _, exception := range p.dateTimes(prop) {
+				exceptions = append(exceptions, exception.time)
+			}
+		case "ATTENDEE":
+			e.Attendees = append(e.Attendees, participantFromContentLine(prop.contentLine))
+		case "ATTACH":
+			if attachment, ok := p.attachment(prop); ok {
+				e.Attachments = append(e.Attachments, attachment)
+			}
+		case "CATEGORIES":
+			e.Categories = append(e.Categories, p.textList(prop)...)
+		case "GEO":
+			e.Geo = p.geo(prop)
+		case "URL":
+			e.URL = prop.Value
+		case "RESOURCES":
+			e.Resources = append(e.Resources, p.textList(prop)...)
+		case "CONTACT":
+			e.Contact = p.text(prop)
+		case "COMMENT":
+			e.Comments = append(e.Comments, p.text(prop))
+		case "RELATED-TO":
+			e.Relations = append(e.Relations, p.relation(prop))
+		}
+	}
+	for _, child := range c.Components {
+		if child.Name == "VALARM" {
+			e.Reminders = append(e.Reminders, p.decodeReminder(child))
+		}
+	}
+
+	if !hasStart {
+		return e
+	}
+	e.StartDate = start.time
+	e.TimeZone = start.timeZone
+	if e.TimeZone == "" {
+		e.TimeZone = TimeZone(timezones.UTC)
+	}
+
+	if rule != nil && rule.frequency.Valid() {
+		rec := Recurrences{
+			Frequency:  rule.frequency,
+			Day:        start.time.Weekday(),
+			StartTime:  clockTime(start.time),
+			Exceptions: exceptions,
+		}
+		if rule.hasDay {
+			rec.Day = rule.day
+		}
+		switch {
+		case hasEnd:
+			rec.EndTime = rec.StartTime.Add(end.time.Sub(start.time))
+		case duration != nil:
+			rec.Duration = duration
+		case start.date:
+			rec.Duration = &Duration{Days: 1}
+		default:
+			rec.EndTime = rec.StartTime
+		}
+		e.Recurrences = []Recurrences{rec}
+		e.EndDate = rule.last(start.time, &rec)
+		if rule.until.IsZero() && rule.count == 0 {
+			p.unsupported(rule.pos, "RRULE without UNTIL or COUNT", fmt.Sprintf("ended the series %d year after it starts", unboundedRecurrenceYears))
+		}
+		return e
+	}
+
+	switch {
+	case hasEnd:
+		e.EndDate = end.time
+	case duration != nil:
+		e.Duration = duration
+	case start.date:
+		e.EndDate = start.time.AddDate(0, 0, 1)
+	default:
+		e.EndDate = start.time
+	}
+	return e
+}
+
+// Reads a VTODO
+//
+// Dates are read as midnight, and times without a time zone stay floating.
+func (p *parser) decodeTodo(c *rawComponent) Todo {
+	var t Todo
+	var start, due parsedTime
+	var hasStart, hasDue bool
+	var duration *Duration
+	var rule *recurrenceRule
+	var exceptions []time.Time
+
+	for i := range c.Properties {
+		prop := &c.Properties[i]
+		switch prop.Name {
+		case "UID":
+			t.UID = prop.Value
+		case "SEQUENCE":
+			if sequence, ok := p.integer(prop); ok {
+				t.Sequence = sequence
+			}
+		case "LAST-MODIFIED":
+			if modified, ok := p.dateTime(prop); ok {
+				t.LastModified = &modified.time
+			}
+		case "SUMMARY":
+			t.Summary = p.text(prop)
+		case "DESCRIPTION":
+			t.Description = p.text(prop)
+		case "DTSTART":
+			start, hasStart = p.dateTime(prop)
+		case "DUE":
+			due, hasDue = p.dateTime(prop)
+		case "DURATION":
+			if d, ok := p.duration(prop); ok {
+				duration = &d
+			}
+		case "COMPLETED":
+			if completed, ok := p.dateTime(prop); ok {
+				t.Completed = &completed.time
+			}
+		case "STATUS":
+			t.Status = TodoStatus(strings.ToUpper(prop.Value))
+		case "PRIORITY":
+			// 0 is an undefined priority
+			if priority, ok := p.integer(prop); ok && priority != 0 {
+				t.Priority = &priority
+			}
+		case "PERCENT-COMPLETE":
+			if percent, ok := p.integer(prop); ok {
+				t.PercentComplete = &percent
+			}
+		case "ORGANIZER":
+			t.Organizer = participantFromContentLine(prop.contentLine)
+		case "RRULE":
+			rule = p.recurrenceRule(prop)
+		case "EXDATE":
+			for _, exception := range p.dateTimes(prop) {
+				exceptions = append(exceptions, exception.time)
+			}
+		case "ATTACH":
+			if attachment, ok := p.attachment(prop); ok {
+				t.Attachments = append(t.Attachments, attachment)
+			}
+		case "RELATED-TO":
+			t.Relations = append(t.Relations, p.relation(prop))
+		}
+	}
+	for _, child := range c.Components {
+		if child.Name == "VALARM" {
+			t.Reminders = append(t.Reminders, p.decodeReminder(child))
+		}
+	}
+
+	if hasStart {
+		t.StartDate = &start.time
+		t.TimeZone = start.timeZone
+	}
+	if !hasDue && hasStart && duration != nil {
+		due, hasDue = parsedTime{time: duration.AddTo(start.time), timeZone: start.timeZone}, true
+	}
+	if hasDue {
+		t.Due = &due.time
+		if t.TimeZone == "" {
+			t.TimeZone = due.timeZone
+		}
+	}
+
+	if rule != nil && rule.frequency.Valid() && (hasStart || hasDue) {
+		anchor := start
+		if !hasStart {
+			anchor = due
+		}
+		rec := &Recurrences{
+			Frequency:  rule.frequency,
+			Day:        anchor.time.Weekday(),
+			Exceptions: exceptions,
+			Until:      rule.until,
+		}
+		if rule.hasDay {
+			rec.Day = rule.day
+		}
+		switch {
+		case hasStart && hasDue:
+			rec.StartTime = clockTime(start.time)
+			rec.EndTime = rec.StartTime.Add(due.time.Sub(start.time))
+		case hasDue:
+			rec.StartTime = clockTime(stripTime(due.time))
+			rec.EndTime = clockTime(due.time)
+		default:
+			rec.StartTime = clockTime(start.time)
+			rec.EndTime = rec.StartTime
+		}
+		if rule.count > 0 && rec.Until.IsZero() {
+			rec.Until = rule.last(anchor.time, rec)
+		}
+		t.Recurrence = rec
+	}
+	return t
+}
+
+// Reads a VJOURNAL
+func (p *parser) decodeJournal(c *rawComponent) Journal {
+	var j Journal
+	descriptions := 0
+	for i := range c.Properties {
+		prop := &c.Properties[i]
+		switch prop.Name {
+		case "UID":
+			j.UID = prop.Value
+		case "SEQUENCE":
+			if sequence, ok := p.integer(prop); ok {
+				j.Sequence = sequence
+			}
+		case "LAST-MODIFIED":
+			if modified, ok := p.dateTime(prop); ok {
+				j.LastModified = &modified.time
+			}
+		case "SUMMARY":
+			j.Summary = p.text(prop)
+		case "DESCRIPTION":
+			if descriptions == 0 {
+				j.Description = p.text(prop)
+			} else {
+				j.Descriptions = append(j.Descriptions, p.text(prop))
+			}
+			descriptions++
+		case "STATUS":
+			j.Status = JournalStatus(strings.ToUpper(prop.Value))
+		case "DTSTART":
+			if start, ok := p.dateTime(prop); ok {
+				j.StartDate = &start.time
+				j.TimeZone = start.timeZone
+			}
+		case "RDATE":
+			for _, date := range p.dateTimes(prop) {
+				j.RecurrenceDates = append(j.RecurrenceDates, date.time)
+			}
+		case "CATEGORIES":
+			j.Categories = append(j.Categories, p.textList(prop)...)
+		case "ORGANIZER":
+			j.Organizer = participantFromContentLine(prop.contentLine)
+		case "ATTENDEE":
+			j.Attendees = append(j.Attendees, participantFromContentLine(prop.contentLine))
+		case "ATTACH":
+			if attachment, ok := p.attachment(prop); ok {
+				j.Attachments = append(j.Attachments, attachment)
+			}
+		case "RELATED-TO":
+			j.Relations = append(j.Relations, p.relation(prop))
+		}
+	}
+	return j
+}
+
+// Reads a VALARM
+func (p *parser) decodeReminder(c *rawComponent) Reminder {
+	var r Reminder
+	for i := range c.Properties {
+		prop := &c.Properties[i]
+		switch prop.Name {
+		case "UID":
+			r.UID = prop.Value
+		case "ACTION":
+			r.Action = ReminderAction(strings.ToUpper(prop.Value))
+		case "DESCRIPTION":
+			r.Description = p.text(prop)
+		case "SUMMARY":
+			r.Summary = p.text(prop)
+		case "TRIGGER":
+			if value, _ := prop.param("VALUE"); strings.EqualFold(value, "DATE-TIME") {
+				if at, ok := p.dateTime(prop); ok {
+					r.TriggerAt = &at.time
+				}
+				continue
+			}
+			if trigger, ok := p.duration(prop); ok {
+				r.Trigger = trigger.Approximate()
+			}
+			if related, _ := prop.param("RELATED"); strings.EqualFold(related, string(EndTriggerRelation)) {
+				r.TriggerRelation = EndTriggerRelation
+			}
+		case "REPEAT":
+			if repeat, ok := p.integer(prop); ok {
+				r.Repeat = &repeat
+			}
+		case "DURATION":
+			if interval, ok := p.duration(prop); ok {
+				r.RepeatInterval = interval.Approximate()
+			}
+		case "ATTENDEE":
+			r.Attendees = append(r.Attendees, participantFromContentLine(prop.contentLine))
+		case "ATTACH":
+			if attachment, ok := p.attachment(prop); ok {
+				r.Attachments = append(r.Attachments, attachment)
+			}
+		case "ACKNOWLEDGED":
+			if acknowledged, ok := p.dateTime(prop); ok {
+				r.Acknowledged = &acknowledged.time
+			}
+		case "RELATED-TO":
+			if relType, _ := prop.param("RELTYPE"); strings.EqualFold(relType, "SNOOZE") {
+				r.Snoozes = prop.Value
+			}
+		case "PROXIMITY":
+			r.Proximity = Proximity(strings.ToUpper(prop.Value))
+		}
+	}
+	return r
+}
+
+// A DATE or DATE-TIME value
+type parsedTime struct {
+	time time.Time
+
+	// Time zone of the TZID, or UTC for a UTC time. Empty for floating times and dates
+	timeZone TimeZone
+
+	// Whether the value is a date, without a time
+	date bool
+}
+
+// Reads a DATE or DATE-TIME property
+func (p *parser) dateTime(prop *rawProperty) (parsedTime, bool) {
+	times := p.dateTimes(prop)
+	if len(times) == 0 {
+		return parsedTime{}, false
+	}
+	return times[0], true
+}
+
+// Reads a property holding a comma-separated list of dates or date-times, such as EXDATE
+//
+// Values that can not be read are skipped.
+func (p *parser) dateTimes(prop *rawProperty) []parsedTime {
+	var timeZone TimeZone
+	location := time.UTC
+	defined := false
+	if tzid, found := prop.param("TZID"); found {
+		tz, err := ParseTimeZone(tzid)
+		if err == nil {
+			if loc, err := tz.Location(); err == nil {
+				timeZone, location = tz, loc
+			}
+		}
+		if loc, found := p.timeZones[tzid]; found && timeZone == "" {
+			// Times in a zone only the data defines are read as UTC
+			timeZone, location, defined = TimeZone(timezones.UTC), loc, true
+		}
+		if timeZone == "" {
+			if p.problem(prop.source.position(0), fmt.Sprintf("unknown time zone %q", tzid), "read the time as UTC") != nil {
+				return nil
+			}
+			timeZone = TimeZone(timezones.UTC)
+		}
+	}
+	valueType, _ := prop.param("VALUE")
+
+	var times []parsedTime
+	offset := 0
+	for _, value := range strings.Split(prop.Value, ",") {
+		parsed, ok := p.timeValue(prop, offset, value, strings.EqualFold(valueType, "DATE"), timeZone, location)
+		if ok && defined {
+			parsed.time = parsed.time.UTC()
+		}
+		if ok {
+			times = append(times, parsed)
+		}
+		offset += len(value) + 1
+	}
+	return times
+}
+
+// Reads a single DATE or DATE-TIME value at offset in the value of the property
+func (p *parser) timeValue(prop *rawProperty, offset int, value string, date bool, timeZone TimeZone, location *time.Location) (parsedTime, bool) {
+	if date || (len(value) == 8 && !strings.ContainsAny(value, "-/")) {
+		if t, err := time.ParseInLocation("20060102", value, time.UTC); err == nil {
+			return parsedTime{time: t, date: true}, true
+		}
+	} else if strings.HasSuffix(value, "Z") {
+		if t, err := time.Parse(iCalTimeLayout+"Z", value); err == nil {
+			return parsedTime{time: t, timeZone: TimeZone(timezones.UTC)}, true
+		}
+	} else if t, err := time.ParseInLocation(iCalTimeLayout, value, location); err == nil {
+		return parsedTime{time: t, timeZone: timeZone}, true
+	}
+
+	message := fmt.Sprintf("invalid %s value %q", prop.Name, value)
+	pos := prop.valuePosition(offset)
+	for _, layout := range lenientTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			if p.problem(pos, message, "read it as "+timeToICal(t)) != nil {
+				return parsedTime{}, false
+			}
+			if strings.HasSuffix(value, "Z") {
+				return parsedTime{time: t.UTC(), timeZone: TimeZone(timezones.UTC)}, true
+			}
+			return parsedTime{time: t, timeZone: timeZone}, true
+		}
+	}
+	for _, layout := range lenientDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			if p.problem(pos, message, "read it as "+t.Format("20060102")) != nil {
+				return parsedTime{}, false
+			}
+			return parsedTime{time: t, date: true}, true
+		}
+	}
+	p.problem(pos, message, "skipped the value")
+	return parsedTime{}, false
+}
+
+// Reads a TEXT property, unescaping it
+//
+// Commas and semicolons must be escaped in a single text value, ParseLenient reads them as text.
+func (p *parser) text(prop *rawProperty) string {
+	var builder strings.Builder
+	reported := false
+	value := prop.Value
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value):
+			i++
+			if value[i] == 'n' || value[i] == 'N' {
+				builder.WriteByte('\n')
+			} else {
+				builder.WriteByte(value[i])
+			}
+		case (c == ',' || c == ';') && !reported:
+			reported = true
+			if p.problem(prop.valuePosition(i), fmt.Sprintf("unescaped %q in %s", c, prop.Name), "read it as text") != nil {
+				return ""
+			}
+			builder.WriteByte(c)
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return builder.String()
+}
+
+// Reads a property holding a comma-separated list of TEXT values, such as CATEGORIES
+func (p *parser) textList(prop *rawProperty) []string {
+	var values []string
+	var builder strings.Builder
+	value := prop.Value
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value):
+			i++
+			if value[i] == 'n' || value[i] == 'N' {
+				builder.WriteByte('\n')
+			} else {
+				builder.WriteByte(value[i])
+			}
+		case c == ',':
+			values = append(values, builder.String())
+			builder.Reset()
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return append(values, builder.String())
+}
+
+// Reads an INTEGER property
+func (p *parser) integer(prop *rawProperty) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(prop.Value))
+	if err != nil {
+		p.problem(prop.valuePosition(0), fmt.Sprintf("invalid %s value %q", prop.Name, prop.Value), "skipped the property")
+		return 0, false
+	}
+	return n, true
+}
+
+// Reads a DURATION property
+func (p *parser) duration(prop *rawProperty) (Duration, bool) {
+	d, err := ParseDuration(prop.Value)
+	if err != nil {
+		p.problem(prop.valuePosition(0), fmt.Sprintf("invalid %s value %q", prop.Name, prop.Value), "skipped the property")
+		return Duration{}, false
+	}
+	return d, true
+}
+
+// Reads an ATTACH property
+func (p *parser) attachment(prop *rawProperty) (Attachment, bool) {
+	attachment, err := attachmentFromContentLine(prop.contentLine)
+	if err != nil {
+		p.problem(prop.valuePosition(0), "invalid ATTACH value", "skipped the property")
+		return Attachment{}, false
+	}
+	return *attachment, true
+}
+
+// Reads a GEO property, "latitude;longitude"
+func (p *parser) geo(prop *rawProperty) *Geo {
+	latitude, longitude, found := strings.Cut(prop.Value, ";")
+	lat, latErr := strconv.ParseFloat(latitude, 64)
+	lon, lonErr := strconv.ParseFloat(longitude, 64)
+	if !found || latErr != nil || lonErr != nil {
+		p.problem(prop.valuePosition(0), fmt.Sprintf("invalid GEO value %q", prop.Value), "skipped the property")
+		return nil
+	}
+	return &Geo{Latitude: lat, Longitude: lon}
+}
+
+// Reads a RELATED-TO property
+func (p *parser) relation(prop *rawProperty) Relation {
+	relation := Relation{UID: prop.Value}
+	if relType, found := prop.param("RELTYPE"); found {
+		relation.Type = RelationType(strings.ToUpper(relType))
+	}
+	if occurrence, found := prop.param("X-RECURRENCE-ID"); found {
+		if t, err := time.Parse(iCalTimeLayout+"Z", occurrence); err == nil {
+			relation.Occurrence = t
+		}
+	}
+	return relation
+}
+
+// Reads an ORGANIZER or ATTENDEE property
+func participantFromContentLine(cl contentLine) Participant {
+	participant := Participant{Email: addressFromCalAddress(cl.Value)}
+	participant.Name, _ = cl.param("CN")
+	if userType, found := cl.param("CUTYPE"); found {
+		participant.Type = CalendarUserType(strings.ToUpper(userType))
+	}
+	if role, found := cl.param("ROLE"); found {
+		participant.Role = ParticipantRole(strings.ToUpper(role))
+	}
+	if status, found := cl.param("PARTSTAT"); found {
+		participant.Status = ParticipationStatus(strings.ToUpper(status))
+	}
+	if rsvp, found := cl.param("RSVP"); found {
+		value := strings.EqualFold(rsvp, "TRUE")
+		participant.RSVP = &value
+	}
+	if sentBy, found := cl.param("SENT-BY"); found {
+		participant.SentBy = addressFromCalAddress(sentBy)
+	}
+	participant.Directory, _ = cl.param("DIR")
+	participant.Language, _ = cl.param("LANGUAGE")
+	for _, address := range cl.params("DELEGATED-TO") {
+		participant.DelegatedTo = append(participant.DelegatedTo, addressFromCalAddress(address))
+	}
+	for _, address := range cl.params("DELEGATED-FROM") {
+		participant.DelegatedFrom = append(participant.DelegatedFrom, addressFromCalAddress(address))
+	}
+	for _, address := range cl.params("MEMBER") {
+		participant.Members = append(participant.Members, addressFromCalAddress(address))
+	}
+	return participant
+}
+
+// The parts of an RRULE the structs can express
+//
+// Parts like INTERVAL or BYMONTHDAY have no field, so they are reported and not read.
+type recurrenceRule struct {
+	frequency Frequency
+
+	// The first day of BYDAY, when set
+	day    time.Weekday
+	hasDay bool
+
+	until time.Time
+	count int
+
+	// Where the RRULE property is
+	pos position
+}
+
+// Frequencies of RFC 5545 the structs have no Frequency for
+var unsupportedFrequencies = map[Frequency]bool{"SECONDLY": true, "MINUTELY": true, "HOURLY": true}
+
+// Reads an RRULE property, reporting every part the structs can not express
+//
+// Valid parts the structs can not express are only reported in lenient mode, invalid parts are problems.
+// WKST is read without a diagnostic, it only changes rules with an INTERVAL or BYWEEKNO.
+func (p *parser) recurrenceRule(prop *rawProperty) *recurrenceRule {
+	rule := &recurrenceRule{pos: prop.valuePosition(0)}
+	offset := 0
+	for _, part := range strings.Split(prop.Value, ";") {
+		name, value, _ := strings.Cut(part, "=")
+		name = strings.ToUpper(name)
+		partOffset, valueOffset := offset, offset+len(name)+1
+		offset += len(part) + 1
+
+		switch name {
+		case "FREQ":
+			rule.frequency = Frequency(strings.ToUpper(value))
+			switch {
+			case rule.frequency.Valid():
+			case unsupportedFrequencies[rule.frequency]:
+				p.unsupported(prop.valuePosition(valueOffset), fmt.Sprintf("unsupported RRULE FREQ %q", value), "read it as not recurring")
+			default:
+				p.problem(prop.valuePosition(valueOffset), fmt.Sprintf("invalid RRULE FREQ %q", value), "read it as not recurring")
+			}
+		case "BYDAY":
+			days := strings.Split(value, ",")
+			// An ordinal such as the -1 of -1FR
+			first := strings.TrimLeft(days[0], "+-0123456789")
+			day, err := DayOfWeekFromString(first)
+			if err != nil {
+				p.problem(prop.valuePosition(valueOffset), fmt.Sprintf("invalid RRULE BYDAY %q", value), "ignored the BYDAY")
+				continue
+			}
+			rule.day, rule.hasDay = day, true
+			switch {
+			case len(days) > 1:
+				p.unsupported(prop.valuePosition(valueOffset), fmt.Sprintf("unsupported RRULE BYDAY %q with more than one day", value), "read only "+days[0])
+			case first != days[0]:
+				p.unsupported(prop.valuePosition(valueOffset), fmt.Sprintf("unsupported RRULE BYDAY %q with an ordinal", value), "read it as every "+first)
+			}
+		case "UNTIL":
+			if until, ok := p.timeValue(prop, valueOffset, value, false, "", time.UTC); ok {
+				rule.until = until.time
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				p.problem(prop.valuePosition(valueOffset), fmt.Sprintf("invalid COUNT %q", value), "ignored the COUNT")
+				continue
+			}
+			rule.count = count
+		case "WKST", "":
+			// The library writes RRULEs ending with a semicolon
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				p.problem(prop.valuePosition(valueOffset), fmt.Sprintf("invalid RRULE INTERVAL %q", value), "read it as INTERVAL=1")
+				continue
+			}
+			if interval == 1 {
+				continue
+			}
+			p.unsupported(prop.valuePosition(valueOffset), fmt.Sprintf("unsupported RRULE INTERVAL %q", value), "read it as INTERVAL=1")
+		default:
+			p.unsupported(prop.valuePosition(partOffset), fmt.Sprintf("unsupported RRULE part %s", name), "ignored the "+name)
+		}
+	}
+	if rule.frequency == "" {
+		p.problem(rule.pos, "RRULE without FREQ", "read it as not recurring")
+	}
+	return rule
+}
+
+// Start of the last occurrence of the rule, for a series starting at start
+func (r *recurrenceRule) last(start time.Time, rec *Recurrences) time.Time {
+	if !r.until.IsZero() {
+		return r.until
+	}
+	if r.count > 0 {
+		last := start
+		for i := 1; i < r.count; i++ {
+			last = rec.advance(last)
+		}
+		return last
+	}
+	return start.AddDate(unboundedRecurrenceYears, 0, 0)
+}
+
+// The time of day of t, on the date the structs use for times of day
+func clockTime(t time.Time) time.Time {
+	return time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
@@ -0,0 +1,280 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	data := calendarData(
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"SEQUENCE:3",
+		"LAST-MODIFIED:20250102T080000Z",
+		"DTSTART;TZID=Europe/Paris:20250106T090000",
+		"DTEND;TZID=Europe/Paris:20250106T091500",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4",
+		"EXDATE;TZID=Europe/Paris:20250113T090000,20250120T090000",
+		`SUMMARY:Standup\, daily`,
+		`DESCRIPTION:First line\nSecond line`,
+		"ORGANIZER;CN=Jane:mailto:jane@example.com",
+		"ATTENDEE;CN=John;ROLE=OPT-PARTICIPANT;PARTSTAT=ACCEPTED;RSVP=TRUE;DELEGATED-TO=\"mailto:a@example.com\",\"mailto:b@example.com\":mailto:john.doe%2Bical@example.com",
+		`CATEGORIES:Work,Team\,Daily`,
+		"GEO:48.85;2.35",
+		"RELATED-TO;RELTYPE=CHILD:notes@example.com",
+		"X-CUSTOM:kept out of the structs",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Standup soon",
+		"TRIGGER;RELATED=END:-PT5M",
+		"REPEAT:2",
+		"DURATION:PT1M",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	cal, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	event := cal.Events[0]
+
+	if event.Title != "Standup, daily" || event.Description != "First line\nSecond line" {
+		t.Errorf("Title, Description = %q, %q", event.Title, event.Description)
+	}
+	if event.TimeZone != "Europe/Paris" || event.StartDate.Format(time.RFC3339) != "2025-01-06T09:00:00+01:00" {
+		t.Errorf("StartDate = %v in %s, want 09:00 in Europe/Paris", event.StartDate, event.TimeZone)
+	}
+	if event.EndDate.Format(time.RFC3339) != "2025-01-27T09:00:00+01:00" {
+		t.Errorf("EndDate = %v, want the fourth occurrence", event.EndDate)
+	}
+	if len(event.Recurrences) != 1 {
+		t.Fatalf("Recurrences = %+v, want 1", event.Recurrences)
+	}
+	rec := event.Recurrences[0]
+	if rec.Frequency != WeeklyFrequency || rec.Day != time.Monday || rec.StartTime.Hour() != 9 || rec.EndTime.Minute() != 15 || len(rec.Exceptions) != 2 {
+		t.Errorf("Recurrences[0] = %+v", rec)
+	}
+
+	if event.Organizer == nil || event.Organizer.Email != "jane@example.com" || event.Organizer.Name != "Jane" {
+		t.Errorf("Organizer = %+v", event.Organizer)
+	}
+	attendee := event.Attendees[0]
+	if attendee.Email != "john.doe+ical@example.com" || attendee.Role != OptionalRole || attendee.Status != AcceptedParticipation ||
+		attendee.RSVP == nil || !*attendee.RSVP || !equalStrings(attendee.DelegatedTo, []string{"a@example.com", "b@example.com"}) {
+		t.Errorf("Attendees[0] = %+v", attendee)
+	}
+	if !equalStrings(event.Categories, []string{"Work", "Team,Daily"}) {
+		t.Errorf("Categories = %q", event.Categories)
+	}
+	if event.Geo == nil || event.Geo.Latitude != 48.85 || event.Geo.Longitude != 2.35 {
+		t.Errorf("Geo = %+v", event.Geo)
+	}
+	if len(event.Relations) != 1 || event.Relations[0].Type != ChildRelation || event.Relations[0].UID != "notes@example.com" {
+		t.Errorf("Relations = %+v", event.Relations)
+	}
+	if event.Sequence != 3 || event.LastModified == nil || !event.LastModified.Equal(time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Sequence = %d, LastModified = %v", event.Sequence, event.LastModified)
+	}
+
+	reminder := event.Reminders[0]
+	if reminder.Action != DisplayReminderAction || reminder.Trigger != -5*time.Minute || reminder.TriggerRelation != EndTriggerRelation ||
+		reminder.Repeat == nil || *reminder.Repeat != 2 || reminder.RepeatInterval != time.Minute {
+		t.Errorf("Reminders[0] = %+v", reminder)
+	}
+
+	cal.Name = "Decoded"
+	if err := cal.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestDecodeEventWithoutRule(t *testing.T) {
+	var tests = []struct {
+		name     string
+		lines    []string
+		end      string
+		duration *Duration
+	}{
+		{"date", []string{"DTSTART;VALUE=DATE:20250106"}, "2025-01-07T00:00:00Z", nil},
+		{"floating", []string{"DTSTART:20250106T090000", "DTEND:20250106T100000"}, "2025-01-06T10:00:00Z", nil},
+		{"duration", []string{"DTSTART:20250106T090000Z", "DURATION:PT30M"}, "0001-01-01T00:00:00Z", &Duration{Time: 30 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:a", "SUMMARY:A"}, tt.lines...)
+			cal, err := Parse(strings.NewReader(calendarData(append(lines, "END:VEVENT", "END:VCALENDAR")...)))
+			if err != nil {
+				t.Fatalf("Parse() returned error: %v", err)
+			}
+			event := cal.Events[0]
+			if event.TimeZone != "UTC" {
+				t.Errorf("TimeZone = %q, want UTC", event.TimeZone)
+			}
+			if event.EndDate.Format(time.RFC3339) != tt.end {
+				t.Errorf("EndDate = %v, want %s", event.EndDate, tt.end)
+			}
+			if (event.Duration == nil) != (tt.duration == nil) || (tt.duration != nil && *event.Duration != *tt.duration) {
+				t.Errorf("Duration = %v, want %v", event.Duration, tt.duration)
+			}
+		})
+	}
+}
+
+func TestDecodeRecurrenceRule(t *testing.T) {
+	var tests = []struct {
+		rrule     string
+		frequency Frequency
+		day       time.Weekday
+		expected  []string
+
+		// Whether Parse fails, as the RRULE is invalid and not only unsupported
+		invalid bool
+	}{
+		{"FREQ=WEEKLY;WKST=SU;BYDAY=MO;COUNT=4", WeeklyFrequency, time.Monday, nil, false},
+		{"FREQ=WEEKLY;INTERVAL=1;UNTIL=20250203T080000Z", WeeklyFrequency, time.Monday, nil, false},
+		{"FREQ=WEEKLY;INTERVAL=2;COUNT=4", WeeklyFrequency, time.Monday,
+			[]string{`line 5, column 28: unsupported RRULE INTERVAL "2", read it as INTERVAL=1`}, false},
+		{"FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4", WeeklyFrequency, time.Tuesday,
+			[]string{`line 5, column 25: unsupported RRULE BYDAY "TU,TH" with more than one day, read only TU`}, false},
+		{"FREQ=MONTHLY;BYDAY=-1FR;COUNT=4", MonthlyFrequency, time.Friday,
+			[]string{`line 5, column 26: unsupported RRULE BYDAY "-1FR" with an ordinal, read it as every FR`}, false},
+		{"FREQ=MONTHLY;BYMONTHDAY=15;COUNT=4", MonthlyFrequency, time.Monday,
+			[]string{"line 5, column 20: unsupported RRULE part BYMONTHDAY, ignored the BYMONTHDAY"}, false},
+		{"FREQ=HOURLY;COUNT=4", "", 0,
+			[]string{`line 5, column 12: unsupported RRULE FREQ "HOURLY", read it as not recurring`}, false},
+		{"FREQ=DAILY", DailyFrequency, time.Monday,
+			[]string{"line 5, column 7: RRULE without UNTIL or COUNT, ended the series 1 year after it starts"}, false},
+		{"FREQ=SOMETIMES;COUNT=4", "", 0,
+			[]string{`line 5, column 12: invalid RRULE FREQ "SOMETIMES", read it as not recurring`}, true},
+		{"FREQ=WEEKLY;INTERVAL=0;COUNT=4", WeeklyFrequency, time.Monday,
+			[]string{`line 5, column 28: invalid RRULE INTERVAL "0", read it as INTERVAL=1`}, true},
+		{"FREQ=WEEKLY;BYDAY=XX;COUNT=4", WeeklyFrequency, time.Monday,
+			[]string{`line 5, column 25: invalid RRULE BYDAY "XX", ignored the BYDAY`}, true},
+		{"COUNT=4", "", 0,
+			[]string{"line 5, column 7: RRULE without FREQ, read it as not recurring"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rrule, func(t *testing.T) {
+			data := calendarData("BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:a", "DTSTART:20250106T090000Z", "RRULE:"+tt.rrule, "END:VEVENT", "END:VCALENDAR")
+			cal, diagnostics, err := ParseLenient(strings.NewReader(data))
+			if err != nil {
+				t.Fatalf("ParseLenient() returned error: %v", err)
+			}
+			var got []string
+			for _, d := range diagnostics {
+				got = append(got, d.String())
+			}
+			if !equalStrings(got, tt.expected) {
+				t.Errorf("ParseLenient() diagnostics = %q, want %q", got, tt.expected)
+			}
+
+			event := cal.Events[0]
+			if tt.frequency == "" {
+				if len(event.Recurrences) != 0 {
+					t.Errorf("Recurrences = %+v, want none", event.Recurrences)
+				}
+			} else if len(event.Recurrences) != 1 || event.Recurrences[0].Frequency != tt.frequency || event.Recurrences[0].Day != tt.day {
+				t.Errorf("Recurrences = %+v, want %s on %s", event.Recurrences, tt.frequency, tt.day)
+			}
+
+			_, err = Parse(strings.NewReader(data))
+			var parseErr *ParseError
+			if !tt.invalid && err != nil {
+				t.Errorf("Parse() returned error: %v", err)
+			} else if tt.invalid && (!errors.As(err, &parseErr) || !strings.HasPrefix(tt.expected[0], parseErr.Error()+", ")) {
+				t.Errorf("Parse() error = %v, want %s", err, tt.expected[0])
+			}
+		})
+	}
+}
+
+func TestDecodeTodo(t *testing.T) {
+	data := calendarData(
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTODO",
+		"UID:report@example.com",
+		"SUMMARY:Weekly report",
+		"DTSTART;TZID=America/New_York:20250106T090000",
+		"DURATION:PT8H",
+		"RRULE:FREQ=WEEKLY;UNTIL=20250203T140000Z",
+		"EXDATE;TZID=America/New_York:20250113T090000",
+		"STATUS:in-process",
+		"PRIORITY:0",
+		"PERCENT-COMPLETE:40",
+		"RELATED-TO;RELTYPE=DEPENDS-ON:draft@example.com",
+		"END:VTODO",
+		"END:VCALENDAR",
+	)
+	cal, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	todo := cal.Todos[0]
+
+	if todo.Status != InProcessStatus || todo.Priority != nil || todo.PercentComplete == nil || *todo.PercentComplete != 40 {
+		t.Errorf("Status, Priority, PercentComplete = %s, %v, %v", todo.Status, todo.Priority, todo.PercentComplete)
+	}
+	if todo.TimeZone != "America/New_York" || todo.StartDate == nil || todo.Due == nil || todo.Due.Sub(*todo.StartDate) != 8*time.Hour {
+		t.Errorf("StartDate, Due = %v, %v in %s", todo.StartDate, todo.Due, todo.TimeZone)
+	}
+	if todo.Recurrence == nil || todo.Recurrence.Frequency != WeeklyFrequency || todo.Recurrence.EndTime.Hour() != 17 ||
+		todo.Recurrence.Until.Format(time.RFC3339) != "2025-02-03T14:00:00Z" || len(todo.Recurrence.Exceptions) != 1 {
+		t.Errorf("Recurrence = %+v", todo.Recurrence)
+	}
+	if len(todo.Relations) != 1 || todo.Relations[0].Type != DependsOnRelation || todo.Relations[0].UID != "draft@example.com" {
+		t.Errorf("Relations = %+v", todo.Relations)
+	}
+}
+
+func TestDecodeJournal(t *testing.T) {
+	data := calendarData(
+		"BEGIN:VCALENDAR",
+		"BEGIN:VJOURNAL",
+		"UID:notes@example.com",
+		"SUMMARY:Notes",
+		"DTSTART;VALUE=DATE:20250106",
+		"RDATE;VALUE=DATE:20250113,20250120",
+		"DESCRIPTION:First",
+		"DESCRIPTION:Second",
+		"CATEGORIES:Meeting",
+		"ATTENDEE;CN=John;PARTSTAT=ACCEPTED:mailto:john@example.com",
+		"STATUS:FINAL",
+		"END:VJOURNAL",
+		"END:VCALENDAR",
+	)
+	cal, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	journal := cal.Journals[0]
+
+	if journal.Description != "First" || !equalStrings(journal.Descriptions, []string{"Second"}) {
+		t.Errorf("Description, Descriptions = %q, %q", journal.Description, journal.Descriptions)
+	}
+	if journal.StartDate == nil || journal.TimeZone != "" || len(journal.RecurrenceDates) != 2 {
+		t.Errorf("StartDate, RecurrenceDates = %v, %v", journal.StartDate, journal.RecurrenceDates)
+	}
+	if journal.Status != FinalJournal || !journal.HasCategory("Meeting") || journal.Attendees[0].Email != "john@example.com" {
+		t.Errorf("Journal = %+v", journal)
+	}
+	if err := journal.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	p := parser{}
+	prop := &rawProperty{contentLine: contentLine{Name: "DESCRIPTION", Value: `a\\b\;c\,d\Ne`}}
+	if text := p.text(prop); text != "a\\b;c,d\ne" {
+		t.Errorf("text() = %q, want %q", text, "a\\b;c,d\ne")
+	}
+	if p.err != nil {
+		t.Errorf("text() error = %v, want nil", p.err)
+	}
+}
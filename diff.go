@@ -0,0 +1,454 @@
+package ical
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// What happened to a component between two versions of a calendar
+type ChangeType string
+
+const (
+	// The component is only in the new calendar
+	AddedChange ChangeType = "ADDED"
+
+	// The component is only in the old calendar
+	RemovedChange ChangeType = "REMOVED"
+
+	// The component is in both calendars, with different properties
+	ModifiedChange ChangeType = "MODIFIED"
+)
+
+// A component that was added, removed or modified between two versions of a calendar
+type ComponentChange struct {
+	// What happened to the component
+	Type ChangeType
+
+	// Name of the component, e.g. "VEVENT"
+	Component string
+
+	// UID of the component
+	UID string
+
+	// Value of the RECURRENCE-ID of the component
+	//
+	// Empty unless the component overrides a single occurrence of a recurring component
+	RecurrenceID string
+
+	// SUMMARY of the component, as it is in the new calendar unless it was removed
+	Summary string
+
+	// The properties that differ, for ModifiedChange
+	Properties []PropertyChange
+
+	// The component as generated from each calendar, nil when it is not in that calendar
+	old, new *rawComponent
+}
+
+// A property whose lines differ between two versions of a component
+type PropertyChange struct {
+	// Name of the property, e.g. "SUMMARY", or of the components in the component, e.g. "VALARM"
+	Name string
+
+	// The unfolded lines of the property in the old version, empty when the property was added
+	Old []string
+
+	// The unfolded lines of the property in the new version, empty when the property was removed
+	New []string
+}
+
+// The changes between two versions of a calendar, returned by Diff
+type CalendarDiff struct {
+	// Removed and modified components in the order of the old calendar, followed by added components
+	Changes []ComponentChange
+
+	// VTIMEZONEs of each calendar, by TZID, for the iTIP messages
+	oldTimeZones, newTimeZones map[string]*rawComponent
+}
+
+// An iTIP message (RFC 5546) announcing the changes to the components with one UID
+type Update struct {
+	// METHOD of the message
+	//
+	// Possible Values: RequestMethod, PublishMethod, CancelMethod
+	Method ITIPMethod
+
+	// UID of the components in the message
+	UID string
+
+	// The VCALENDAR of the message
+	Data []byte
+}
+
+// METHOD of an iTIP message
+type ITIPMethod string
+
+const (
+	// New and modified events and To-Dos
+	RequestMethod ITIPMethod = "REQUEST"
+
+	// New and modified journal entries, which have no attendees to reply
+	PublishMethod ITIPMethod = "PUBLISH"
+
+	// Removed components
+	CancelMethod ITIPMethod = "CANCEL"
+)
+
+// Diff compares two versions of a calendar, matching their events, To-Dos and journal entries
+// by UID and RECURRENCE-ID.
+//
+// Both calendars are generated and the output is compared, so a change is a difference in what
+// other applications read. DTSTAMP is ignored, as it is the time the calendar was generated.
+// A nil calendar has no components.
+//
+// To-Dos and journal entries without a UID are matched by their position among the To-Dos or journal entries
+// of the calendar, as the UID they are generated with changes every second. Events without a UID are matched
+// by the UID generated from their title and days. Give components a UID to match them reliably.
+func Diff(old, new *Calendar) (*CalendarDiff, error) {
+	oldKeys, oldComponents, oldTimeZones, err := diffComponents(old)
+	if err != nil {
+		return nil, err
+	}
+	newKeys, newComponents, newTimeZones, err := diffComponents(new)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &CalendarDiff{oldTimeZones: oldTimeZones, newTimeZones: newTimeZones}
+	matched := make(map[string]bool)
+	for _, key := range oldKeys {
+		oldComponent := oldComponents[key]
+		newComponent, found := newComponents[key]
+		if !found {
+			d.Changes = append(d.Changes, newComponentChange(RemovedChange, oldComponent, nil))
+			continue
+		}
+		matched[key] = true
+		if properties := propertyChanges(oldComponent, newComponent); len(properties) > 0 {
+			change := newComponentChange(ModifiedChange, oldComponent, newComponent)
+			change.Properties = properties
+			d.Changes = append(d.Changes, change)
+		}
+	}
+	for _, key := range newKeys {
+		if !matched[key] {
+			d.Changes = append(d.Changes, newComponentChange(AddedChange, nil, newComponents[key]))
+		}
+	}
+	return d, nil
+}
+
+// The keys of the events, To-Dos and journal entries of the generated calendar in order,
+// the components by key and the VTIMEZONEs by TZID
+func diffComponents(c *Calendar) ([]string, map[string]*rawComponent, map[string]*rawComponent, error) {
+	var keys []string
+	components := make(map[string]*rawComponent)
+	timeZones := make(map[string]*rawComponent)
+	if c == nil {
+		return keys, components, timeZones, nil
+	}
+	c, positions := withDiffUIDs(c)
+	data, err := c.Generate()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	seen := make(map[string]int)
+	for _, component := range readGenerated(string(data)) {
+		switch {
+		case component.Name == "VTIMEZONE":
+			if tzid, found := component.property("TZID"); found {
+				timeZones[tzid.Value] = component
+			}
+		case modeledComponents[component.Name]:
+			key := diffKey(component)
+			uid := rawValue(component, "UID")
+			if keys := positions[uid]; len(keys) > 0 {
+				key, positions[uid] = keys[0], keys[1:]
+			}
+			seen[key]++
+			if seen[key] > 1 {
+				// Components that share a UID and RECURRENCE-ID are matched in order
+				key += fmt.Sprintf("\x00%d", seen[key])
+			}
+			keys = append(keys, key)
+			components[key] = component
+		}
+	}
+	return keys, components, timeZones, nil
+}
+
+// A copy of the calendar in which the To-Dos and journal entries without a UID have the UID
+// they are generated with, and the keys identifying them by their position instead, by that UID
+func withDiffUIDs(c *Calendar) (*Calendar, map[string][]string) {
+	positions := make(map[string][]string)
+	copied := *c
+	copied.Todos = slices.Clone(c.Todos)
+	for i := range copied.Todos {
+		if todo := &copied.Todos[i]; todo.UID == "" {
+			todo.UID = todo.uid()
+			positions[todo.UID] = append(positions[todo.UID], fmt.Sprintf("VTODO\x00\x00%d", i))
+		}
+	}
+	copied.Journals = slices.Clone(c.Journals)
+	for i := range copied.Journals {
+		if journal := &copied.Journals[i]; journal.UID == "" {
+			journal.UID = journal.uid()
+			positions[journal.UID] = append(positions[journal.UID], fmt.Sprintf("VJOURNAL\x00\x00%d", i))
+		}
+	}
+	return &copied, positions
+}
+
+// Identifies a component across versions of a calendar: its name, UID and RECURRENCE-ID
+func diffKey(c *rawComponent) string {
+	return c.Name + "\x00" + rawValue(c, "UID") + "\x00" + rawValue(c, "RECURRENCE-ID")
+}
+
+// The value of the first property with the name, empty when there is none
+func rawValue(c *rawComponent, name string) string {
+	if prop, found := c.property(name); found {
+		return prop.Value
+	}
+	return ""
+}
+
+func newComponentChange(changeType ChangeType, old, new *rawComponent) ComponentChange {
+	component := new
+	if component == nil {
+		component = old
+	}
+	change := ComponentChange{
+		Type:         changeType,
+		Component:    component.Name,
+		UID:          rawValue(component, "UID"),
+		RecurrenceID: rawValue(component, "RECURRENCE-ID"),
+		old:          old,
+		new:          new,
+	}
+	if summary, found := component.property("SUMMARY"); found {
+		p := parser{lenient: true}
+		change.Summary = p.text(summary)
+	}
+	return change
+}
+
+// The properties and components in the components whose lines differ.
+//
+// DTSTAMP is the time the calendar was generated, and UID only differs for components
+// without one, which are matched by their position.
+func propertyChanges(old, new *rawComponent) []PropertyChange {
+	oldLines, newLines := linesByName(old.Properties), linesByName(new.Properties)
+	var names []string
+	for _, prop := range append(slices.Clone(old.Properties), new.Properties...) {
+		if prop.Name != "DTSTAMP" && prop.Name != "UID" && !slices.Contains(names, prop.Name) {
+			names = append(names, prop.Name)
+		}
+	}
+
+	oldChildren, newChildren := linesByComponent(old.Components), linesByComponent(new.Components)
+	for _, child := range append(slices.Clone(old.Components), new.Components...) {
+		if !slices.Contains(names, child.Name) {
+			names = append(names, child.Name)
+		}
+		oldLines[child.Name] = oldChildren[child.Name]
+		newLines[child.Name] = newChildren[child.Name]
+	}
+
+	var changes []PropertyChange
+	for _, name := range names {
+		if !slices.Equal(oldLines[name], newLines[name]) {
+			changes = append(changes, PropertyChange{Name: name, Old: oldLines[name], New: newLines[name]})
+		}
+	}
+	return changes
+}
+
+// The unfolded lines of the components, by component name
+func linesByComponent(components []*rawComponent) map[string][]string {
+	lines := make(map[string][]string)
+	for _, component := range components {
+		lines[component.Name] = append(lines[component.Name], component.lines()...)
+	}
+	return lines
+}
+
+// Changelog describes the changes for people, a line for every added, removed or modified component,
+// followed by the lines removed from and added to every modified component.
+func (d *CalendarDiff) Changelog() string {
+	var builder strings.Builder
+	for _, change := range d.Changes {
+		builder.WriteString(change.String() + "\n")
+		for _, property := range change.Properties {
+			for _, line := range linesOnlyIn(property.Old, property.New) {
+				builder.WriteString("  - " + line + "\n")
+			}
+			for _, line := range linesOnlyIn(property.New, property.Old) {
+				builder.WriteString("  + " + line + "\n")
+			}
+		}
+	}
+	return builder.String()
+}
+
+// What the changelog calls each component
+var changelogNames = map[string]string{"VEVENT": "event", "VTODO": "To-Do", "VJOURNAL": "journal entry"}
+
+func (c ComponentChange) String() string {
+	var action string
+	switch c.Type {
+	case AddedChange:
+		action = "Added"
+	case RemovedChange:
+		action = "Removed"
+	default:
+		action = "Modified"
+	}
+
+	identity := c.UID
+	if c.RecurrenceID != "" {
+		identity += ", occurrence " + c.RecurrenceID
+	}
+	if c.Summary == "" {
+		return fmt.Sprintf("%s %s (%s)", action, changelogNames[c.Component], identity)
+	}
+	return fmt.Sprintf("%s %s %q (%s)", action, changelogNames[c.Component], c.Summary, identity)
+}
+
+// The lines of a that are not in b, counting repeated lines
+func linesOnlyIn(a, b []string) []string {
+	remaining := make(map[string]int)
+	for _, line := range b {
+		remaining[line]++
+	}
+	var only []string
+	for _, line := range a {
+		if remaining[line] > 0 {
+			remaining[line]--
+			continue
+		}
+		only = append(only, line)
+	}
+	return only
+}
+
+// Updates are the iTIP messages (RFC 5546) that bring attendees of the old calendar up to date:
+// a REQUEST with the added and modified events and To-Dos of every UID, a PUBLISH with those of journal entries
+// and a CANCEL with the removed components.
+//
+// Only the components that changed are sent, so a modified occurrence of a recurring event is sent without
+// the rest of the series. A CANCEL of a whole series replaces the cancellations of its occurrences.
+//
+// Modified components are sent as they are in the new calendar, increase their Sequence on significant changes
+// so attendees apply them. Cancellations are sent with the SEQUENCE of the removed component increased by 1.
+func (d *CalendarDiff) Updates() []Update {
+	type message struct {
+		Update
+		components []*rawComponent
+	}
+	var messages []*message
+	byKey := make(map[string]*message)
+	add := func(method ITIPMethod, uid string, component *rawComponent) {
+		key := string(method) + "\x00" + component.Name + "\x00" + uid
+		if _, found := byKey[key]; !found {
+			byKey[key] = &message{Update: Update{Method: method, UID: uid}}
+			messages = append(messages, byKey[key])
+		}
+		byKey[key].components = append(byKey[key].components, component)
+	}
+
+	cancelledSeries := make(map[string]bool)
+	for _, change := range d.Changes {
+		if change.Type == RemovedChange && change.RecurrenceID == "" {
+			cancelledSeries[change.Component+"\x00"+change.UID] = true
+		}
+	}
+	for _, change := range d.Changes {
+		switch {
+		case change.Type != RemovedChange:
+			method := RequestMethod
+			if change.Component == "VJOURNAL" {
+				method = PublishMethod
+			}
+			add(method, change.UID, change.new)
+		case change.RecurrenceID == "" || !cancelledSeries[change.Component+"\x00"+change.UID]:
+			add(CancelMethod, change.UID, cancellation(change.old))
+		}
+	}
+
+	updates := make([]Update, len(messages))
+	for i, m := range messages {
+		timeZones := d.newTimeZones
+		if m.Method == CancelMethod {
+			timeZones = d.oldTimeZones
+		}
+		m.Data = itipMessage(m.Method, m.components, timeZones)
+		updates[i] = m.Update
+	}
+	return updates
+}
+
+// A component cancelling the removed component, keeping the properties that identify it and its attendees
+func cancellation(removed *rawComponent) *rawComponent {
+	cancelled := &rawComponent{Name: removed.Name}
+	keep := func(names ...string) {
+		for _, name := range names {
+			for _, prop := range removed.properties(name) {
+				cancelled.Properties = append(cancelled.Properties, *prop)
+			}
+		}
+	}
+	sequence, _ := strconv.Atoi(rawValue(removed, "SEQUENCE"))
+
+	keep("UID")
+	cancelled.Properties = append(cancelled.Properties, newRawProperty("DTSTAMP:"+timeToICal(time.Now().UTC())+"Z"))
+	keep("RECURRENCE-ID")
+	cancelled.Properties = append(cancelled.Properties,
+		newRawProperty("SEQUENCE:"+strconv.Itoa(sequence+1)),
+		newRawProperty("STATUS:CANCELLED"),
+	)
+	keep("SUMMARY", "ORGANIZER", "ATTENDEE")
+	return cancelled
+}
+
+// A property for the unfolded content line
+func newRawProperty(line string) rawProperty {
+	cl, _ := parseContentLine(line)
+	return rawProperty{contentLine: cl, source: &rawLine{text: line, folded: foldLine(line)}}
+}
+
+// The VCALENDAR of an iTIP message, with the VTIMEZONEs of the TZIDs the components use
+func itipMessage(method ITIPMethod, components []*rawComponent, timeZones map[string]*rawComponent) []byte {
+	var builder strings.Builder
+	builder.WriteString("BEGIN:VCALENDAR" + lineBreak)
+	for _, property := range calendarProperties {
+		if strings.HasPrefix(property, "METHOD:") {
+			property = "METHOD:" + string(method)
+		}
+		builder.WriteString(property + lineBreak)
+	}
+
+	tzids := make(map[string]bool)
+	for _, component := range components {
+		component.collectTZIDs(tzids)
+	}
+	sorted := make([]string, 0, len(tzids))
+	for tzid := range tzids {
+		sorted = append(sorted, tzid)
+	}
+	slices.Sort(sorted)
+	for _, tzid := range sorted {
+		if timeZone, found := timeZones[tzid]; found {
+			timeZone.write(&builder)
+		}
+	}
+
+	for _, component := range components {
+		component.write(&builder)
+	}
+	builder.WriteString("END:VCALENDAR" + lineBreak)
+	return []byte(builder.String())
+}
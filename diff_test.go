@@ -0,0 +1,309 @@
+package ical
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Two versions of a calendar: an override and a reminder of the standup changed,
+// the To-Do and the offsite were removed, and a journal entry was added
+func diffCalendars(t *testing.T) (*Calendar, *Calendar) {
+	t.Helper()
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"SEQUENCE:1",
+		"SUMMARY:Standup",
+		"DTSTART;TZID=Europe/Paris:20250106T090000",
+		"DTEND;TZID=Europe/Paris:20250106T091500",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4",
+		"ORGANIZER;CN=Jane:mailto:jane@example.com",
+		"ATTENDEE;CN=John:mailto:john@example.com",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Standup soon",
+		"TRIGGER:-PT5M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"RECURRENCE-ID;TZID=Europe/Paris:20250113T090000",
+		"SEQUENCE:2",
+		"SUMMARY:Standup moved",
+		"DTSTART;TZID=Europe/Paris:20250113T110000",
+		"DTEND;TZID=Europe/Paris:20250113T111500",
+		"ORGANIZER;CN=Jane:mailto:jane@example.com",
+		"ATTENDEE;CN=John:mailto:john@example.com",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:offsite@example.com",
+		"SUMMARY:Offsite",
+		"DTSTART;TZID=Europe/Paris:20250120T090000",
+		"DTEND;TZID=Europe/Paris:20250120T170000",
+		"ORGANIZER;CN=Jane:mailto:jane@example.com",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:report@example.com",
+		"SUMMARY:Write report",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}
+	old, updated := roundTripCalendar(t, lines...), roundTripCalendar(t, lines...)
+
+	updated.Events[0].Reminders[0].Trigger = -10 * time.Minute
+	updated.Events[1].Title = "Standup (moved)"
+	updated.Events = updated.Events[:2]
+	updated.Todos = nil
+	if err := updated.AddJournal(Journal{UID: "notes@example.com", Summary: "Notes", Description: "Minutes"}); err != nil {
+		t.Fatalf("AddJournal() returned error: %v", err)
+	}
+	return old, updated
+}
+
+func TestDiff(t *testing.T) {
+	old, updated := diffCalendars(t)
+	diff, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+
+	var tests = []struct {
+		changeType   ChangeType
+		component    string
+		uid          string
+		recurrenceID string
+		summary      string
+		properties   []string
+	}{
+		{ModifiedChange, "VEVENT", "standup@example.com", "", "Standup", []string{"VALARM"}},
+		{ModifiedChange, "VEVENT", "standup@example.com", "20250113T090000", "Standup (moved)", []string{"SUMMARY"}},
+		{RemovedChange, "VEVENT", "offsite@example.com", "", "Offsite", nil},
+		{RemovedChange, "VTODO", "report@example.com", "", "Write report", nil},
+		{AddedChange, "VJOURNAL", "notes@example.com", "", "Notes", nil},
+	}
+	if len(diff.Changes) != len(tests) {
+		t.Fatalf("Diff() = %+v, want %d changes", diff.Changes, len(tests))
+	}
+	for i, tt := range tests {
+		change := diff.Changes[i]
+		var properties []string
+		for _, property := range change.Properties {
+			properties = append(properties, property.Name)
+		}
+		if change.Type != tt.changeType || change.Component != tt.component || change.UID != tt.uid ||
+			change.RecurrenceID != tt.recurrenceID || change.Summary != tt.summary || !slices.Equal(properties, tt.properties) {
+			t.Errorf("Changes[%d] = %+v, want %+v", i, change, tt)
+		}
+	}
+
+	summary := diff.Changes[1].Properties[0]
+	if !slices.Equal(summary.Old, []string{"SUMMARY:Standup moved"}) || !slices.Equal(summary.New, []string{"SUMMARY:Standup (moved)"}) {
+		t.Errorf("Properties[0] = %+v, want the old and new SUMMARY", summary)
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	old, _ := diffCalendars(t)
+	data := generateString(t, old)
+	cal := roundTripCalendar(t, strings.Split(strings.TrimSuffix(data, lineBreak), lineBreak)...)
+
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	created := Create("Team", "Meetings")
+	if err := created.AddEvent(Event{UID: "a@example.com", Title: "A", StartDate: start, EndDate: start.Add(time.Hour), TimeZone: "Europe/Paris"}); err != nil {
+		t.Fatalf("AddEvent() returned error: %v", err)
+	}
+
+	var tests = []struct {
+		name     string
+		old, new *Calendar
+	}{
+		{"generated again", old, cal},
+		{"same calendar", created, created},
+		{"nil calendars", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := Diff(tt.old, tt.new)
+			if err != nil {
+				t.Fatalf("Diff() returned error: %v", err)
+			}
+			if len(diff.Changes) != 0 {
+				t.Errorf("Diff() = %+v, want no changes", diff.Changes)
+			}
+		})
+	}
+}
+
+func TestDiffWithoutUIDs(t *testing.T) {
+	old := Create("Team", "Notes")
+	old.Todos = []Todo{{Summary: "Call Jim"}, {Summary: "Call Jim"}}
+	old.Journals = []Journal{{Summary: "Minutes", Description: "Notes"}}
+
+	updated := Create("Team", "Notes")
+	updated.Todos = []Todo{{Summary: "Call Jim"}, {Summary: "Call Jim back"}}
+	updated.Journals = slices.Clone(old.Journals)
+
+	diff, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+	if len(diff.Changes) != 1 {
+		t.Fatalf("Diff() = %+v, want the second To-Do modified", diff.Changes)
+	}
+	change := diff.Changes[0]
+	if change.Type != ModifiedChange || change.Component != "VTODO" || change.Summary != "Call Jim back" ||
+		len(change.Properties) != 1 || change.Properties[0].Name != "SUMMARY" {
+		t.Errorf("Changes[0] = %+v, want the SUMMARY of the second To-Do modified", change)
+	}
+}
+
+func TestDiffNilCalendar(t *testing.T) {
+	old, _ := diffCalendars(t)
+
+	added, err := Diff(nil, old)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+	removed, err := Diff(old, nil)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+	if len(added.Changes) != 4 || len(removed.Changes) != 4 {
+		t.Fatalf("Diff() = %+v and %+v, want 4 changes each", added.Changes, removed.Changes)
+	}
+	for i := range added.Changes {
+		if added.Changes[i].Type != AddedChange || removed.Changes[i].Type != RemovedChange {
+			t.Errorf("Changes[%d] = %s and %s, want added and removed", i, added.Changes[i].Type, removed.Changes[i].Type)
+		}
+	}
+}
+
+func TestDiffInvalidCalendar(t *testing.T) {
+	if _, err := Diff(&Calendar{}, nil); err == nil {
+		t.Errorf("Diff() = nil error, want the calendar without a name")
+	}
+}
+
+func TestDiffChangelog(t *testing.T) {
+	old, updated := diffCalendars(t)
+	diff, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		`Modified event "Standup" (standup@example.com)`,
+		"  - TRIGGER:-PT5M",
+		"  + TRIGGER:-PT10M",
+		`Modified event "Standup (moved)" (standup@example.com, occurrence 20250113T090000)`,
+		"  - SUMMARY:Standup moved",
+		"  + SUMMARY:Standup (moved)",
+		`Removed event "Offsite" (offsite@example.com)`,
+		`Removed To-Do "Write report" (report@example.com)`,
+		`Added journal entry "Notes" (notes@example.com)`,
+		"",
+	}, "\n")
+	if changelog := diff.Changelog(); changelog != expected {
+		t.Errorf("Changelog() =\n%s\nwant\n%s", changelog, expected)
+	}
+}
+
+func TestDiffUpdates(t *testing.T) {
+	old, updated := diffCalendars(t)
+	// Removing the series cancels the override along with it
+	updated.Events = nil
+	diff, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+
+	updates := diff.Updates()
+	var tests = []struct {
+		method     ITIPMethod
+		uid        string
+		contains   []string
+		notContain []string
+	}{
+		{CancelMethod, "standup@example.com",
+			[]string{"METHOD:CANCEL", "UID:standup@example.com", "SEQUENCE:2\r\n", "STATUS:CANCELLED", "ATTENDEE;CN=John:mailto:john@example.com"},
+			[]string{"RECURRENCE-ID", "RRULE", "BEGIN:VALARM", "BEGIN:VTIMEZONE"}},
+		{CancelMethod, "offsite@example.com",
+			[]string{"UID:offsite@example.com", "SEQUENCE:1\r\n", "SUMMARY:Offsite", "ORGANIZER;CN=Jane:mailto:jane@example.com"},
+			[]string{"BEGIN:VTIMEZONE", "DTSTART"}},
+		{CancelMethod, "report@example.com", []string{"BEGIN:VTODO", "STATUS:CANCELLED"}, nil},
+		{PublishMethod, "notes@example.com", []string{"METHOD:PUBLISH", "BEGIN:VJOURNAL", "SUMMARY:Notes"}, []string{"METHOD:REQUEST"}},
+	}
+	if len(updates) != len(tests) {
+		t.Fatalf("Updates() = %d messages, want %d", len(updates), len(tests))
+	}
+	for i, tt := range tests {
+		update := updates[i]
+		data := string(update.Data)
+		if update.Method != tt.method || update.UID != tt.uid || !strings.HasPrefix(data, "BEGIN:VCALENDAR\r\n") {
+			t.Errorf("Updates()[%d] = %s %s, want %s %s", i, update.Method, update.UID, tt.method, tt.uid)
+		}
+		for _, expected := range tt.contains {
+			if !strings.Contains(data, expected) {
+				t.Errorf("Updates()[%d] = %s, want it to contain %q", i, data, expected)
+			}
+		}
+		for _, unexpected := range tt.notContain {
+			if strings.Contains(data, unexpected) {
+				t.Errorf("Updates()[%d] = %s, want it not to contain %q", i, data, unexpected)
+			}
+		}
+	}
+}
+
+func TestDiffUpdatesModified(t *testing.T) {
+	old, updated := diffCalendars(t)
+	updated.Events[1].Sequence = 3
+	diff, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+
+	updates := diff.Updates()
+	if len(updates) == 0 || updates[0].Method != RequestMethod || updates[0].UID != "standup@example.com" {
+		t.Fatalf("Updates() = %+v, want a REQUEST for the standup first", updates)
+	}
+	request := string(updates[0].Data)
+	for _, expected := range []string{"METHOD:REQUEST", "TZID:Europe/Paris", "TRIGGER:-PT10M", "RECURRENCE-ID;TZID=Europe/Paris:20250113T090000", "SEQUENCE:3"} {
+		if !strings.Contains(request, expected) {
+			t.Errorf("Updates()[0] = %s, want it to contain %q", request, expected)
+		}
+	}
+	if strings.Count(request, "BEGIN:VEVENT") != 2 || strings.Count(request, "BEGIN:VTIMEZONE") != 1 {
+		t.Errorf("Updates()[0] = %s, want both changed events and one VTIMEZONE", request)
+	}
+	if _, err := Parse(strings.NewReader(request)); err != nil {
+		t.Errorf("Parse() of the REQUEST returned error: %v", err)
+	}
+}
+
+func TestDiffUpdatesCancelOccurrence(t *testing.T) {
+	old, updated := diffCalendars(t)
+	updated.Events = updated.Events[:1]
+	updated.Events[0].Reminders[0].Trigger = -5 * time.Minute
+	updated.Journals = nil
+	diff, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff() returned error: %v", err)
+	}
+
+	updates := diff.Updates()
+	if len(updates) != 3 || updates[0].Method != CancelMethod || updates[0].UID != "standup@example.com" {
+		t.Fatalf("Updates() = %+v, want a CANCEL of the occurrence first", updates)
+	}
+	cancel := string(updates[0].Data)
+	for _, expected := range []string{"BEGIN:VTIMEZONE\r\nTZID:Europe/Paris", "RECURRENCE-ID;TZID=Europe/Paris:20250113T090000", "SEQUENCE:3\r\n"} {
+		if !strings.Contains(cancel, expected) {
+			t.Errorf("Updates()[0] = %s, want it to contain %q", cancel, expected)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package ical
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// iCalendar DURATION value, using ISO 8601 durations
+//
+// Weeks and days are nominal: adding one day keeps the same wall-clock time,
+// even across daylight saving transitions. Time is an exact duration.
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-3-6-duration.html
+type Duration struct {
+	// OPTIONAL: Whether the duration points backwards in time
+	Negative bool
+
+	// OPTIONAL: Number of nominal weeks
+	Weeks int
+
+	// OPTIONAL: Number of nominal days
+	Days int
+
+	// OPTIONAL: Exact hours, minutes and seconds
+	Time time.Duration
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// NewDuration converts a time.Duration into a Duration.
+//
+// Every full 24 hours is counted as one nominal day.
+func NewDuration(d time.Duration) Duration {
+	var duration Duration
+	if d < 0 {
+		duration.Negative = true
+		d = -d
+	}
+	duration.Days = int(d / (24 * time.Hour))
+	duration.Time = (d % (24 * time.Hour)).Truncate(time.Second)
+	return duration
+}
+
+// ParseDuration parses an iCalendar duration value.
+//
+// E.g., "PT15M", "-P1DT2H", "P2W"
+func ParseDuration(value string) (Duration, error) {
+	var d Duration
+	match := durationPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil || strings.HasSuffix(match[0], "P") || strings.HasSuffix(match[0], "T") {
+		return d, fmt.Errorf("invalid duration %q", value)
+	}
+
+	numbers := make([]int, 5)
+	for i, s := range match[2:] {
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return d, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		numbers[i] = n
+	}
+
+	d.Negative = match[1] == "-"
+	d.Weeks = numbers[0]
+	d.Days = numbers[1]
+	d.Time = time.Duration(numbers[2])*time.Hour +
+		time.Duration(numbers[3])*time.Minute +
+		time.Duration(numbers[4])*time.Second
+	return d, nil
+}
+
+// String formats the duration as an iCalendar duration value.
+func (d Duration) String() string {
+	var builder strings.Builder
+	if d.Negative {
+		builder.WriteString("-")
+	}
+	builder.WriteString("P")
+
+	// The week form can not be combined with any other unit
+	if d.Weeks > 0 && d.Days == 0 && d.Time == 0 {
+		builder.WriteString(fmt.Sprintf("%dW", d.Weeks))
+		return builder.String()
+	}
+
+	days := d.Weeks*7 + d.Days
+	totalSeconds := int(d.Time.Seconds())
+	hours := totalSeconds / (60 * 60)
+	minutes := (totalSeconds / 60) % 60
+	seconds := totalSeconds % 60
+
+	if days > 0 {
+		builder.WriteString(fmt.Sprintf("%dD", days))
+	}
+
+	hasTime := hours > 0 || minutes > 0 || seconds > 0
+	if hasTime || days == 0 {
+		builder.WriteString("T")
+	}
+
+	if hours > 0 {
+		builder.WriteString(fmt.Sprintf("%dH", hours))
+	}
+	if minutes > 0 {
+		builder.WriteString(fmt.Sprintf("%dM", minutes))
+	}
+	if seconds > 0 || (!hasTime && days == 0) {
+		builder.WriteString(fmt.Sprintf("%dS", seconds))
+	}
+
+	return builder.String()
+}
+
+// AddTo returns t shifted by the duration.
+//
+// Weeks and days are added to the calendar date first, then the exact time is added.
+func (d Duration) AddTo(t time.Time) time.Time {
+	sign := 1
+	if d.Negative {
+		sign = -1
+	}
+	t = t.AddDate(0, 0, sign*(d.Weeks*7+d.Days))
+	return t.Add(time.Duration(sign) * d.Time)
+}
+
+// Approximate converts the duration into a time.Duration, counting every day as 24 hours.
+func (d Duration) Approximate() time.Duration {
+	total := time.Duration(d.Weeks*7+d.Days)*24*time.Hour + d.Time
+	if d.Negative {
+		return -total
+	}
+	return total
+}
+
+// IsZero reports whether the duration has no length.
+func (d Duration) IsZero() bool {
+	return d.Weeks == 0 && d.Days == 0 && d.Time == 0
+}
+
+func (d Duration) valid() bool {
+	return d.Weeks >= 0 && d.Days >= 0 && d.Time >= 0
+}
+
+// A duration that moves forward in time, e.g. the length of an event
+func (d Duration) positive() bool {
+	return d.valid() && !d.Negative && !d.IsZero()
+}
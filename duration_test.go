@@ -0,0 +1,113 @@
+package ical
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationString(t *testing.T) {
+	tests := []struct {
+		duration Duration
+		expected string
+	}{
+		{Duration{}, "PT0S"},
+		{Duration{Weeks: 2}, "P2W"},
+		{Duration{Days: 1}, "P1D"},
+		{Duration{Weeks: 1, Days: 1}, "P8D"},
+		{Duration{Days: 1, Time: 2 * time.Hour}, "P1DT2H"},
+		{Duration{Negative: true, Time: 15 * time.Minute}, "-PT15M"},
+		{Duration{Time: 36 * time.Hour}, "PT36H"},
+		{Duration{Time: 90 * time.Second}, "PT1M30S"},
+	}
+
+	for _, test := range tests {
+		if got := test.duration.String(); got != test.expected {
+			t.Errorf("Duration%+v.String() = %s, want %s", test.duration, got, test.expected)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  Duration
+		expectErr bool
+	}{
+		{"PT15M", Duration{Time: 15 * time.Minute}, false},
+		{"-PT15M", Duration{Negative: true, Time: 15 * time.Minute}, false},
+		{"+P1D", Duration{Days: 1}, false},
+		{"P2W", Duration{Weeks: 2}, false},
+		{"P15DT5H0M20S", Duration{Days: 15, Time: 5*time.Hour + 20*time.Second}, false},
+		{"pt1h", Duration{Time: time.Hour}, false},
+		{"PT0S", Duration{}, false},
+		{"P", Duration{}, true},
+		{"PT", Duration{}, true},
+		{"P1DT", Duration{}, true},
+		{"15M", Duration{}, true},
+		{"P1Y", Duration{}, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseDuration(test.value)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) expected error, got %+v", test.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", test.value, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("ParseDuration(%q) = %+v, want %+v", test.value, got, test.expected)
+		}
+	}
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	for _, value := range []string{"PT0S", "P2W", "-P1DT1H", "PT1H30M", "P3DT4H5M6S"} {
+		d, err := ParseDuration(value)
+		if err != nil {
+			t.Fatalf("ParseDuration(%q) returned error: %v", value, err)
+		}
+		if d.String() != value {
+			t.Errorf("ParseDuration(%q).String() = %s", value, d.String())
+		}
+	}
+}
+
+func TestDurationAddToAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// Daylight saving time starts on 2025-03-09 in New York
+	start := time.Date(2025, 3, 8, 9, 0, 0, 0, loc)
+
+	nominal := Duration{Days: 1}.AddTo(start)
+	if nominal.Hour() != 9 || nominal.Day() != 9 {
+		t.Errorf("Duration{Days: 1}.AddTo() = %v, want 9:00 on the next day", nominal)
+	}
+
+	exact := Duration{Time: 24 * time.Hour}.AddTo(start)
+	if exact.Hour() != 10 {
+		t.Errorf("Duration{Time: 24h}.AddTo() = %v, want 10:00 on the next day", exact)
+	}
+
+	week := Duration{Weeks: 1, Negative: true}.AddTo(start)
+	if !week.Equal(time.Date(2025, 3, 1, 9, 0, 0, 0, loc)) {
+		t.Errorf("Duration{Weeks: 1, Negative: true}.AddTo() = %v", week)
+	}
+}
+
+func TestNewDuration(t *testing.T) {
+	d := NewDuration(-(25*time.Hour + 30*time.Minute))
+	expected := Duration{Negative: true, Days: 1, Time: time.Hour + 30*time.Minute}
+	if d != expected {
+		t.Errorf("NewDuration() = %+v, want %+v", d, expected)
+	}
+	if d.Approximate() != -(25*time.Hour + 30*time.Minute) {
+		t.Errorf("Approximate() = %v", d.Approximate())
+	}
+}
@@ -3,16 +3,28 @@ package ical
 import "fmt"
 
 const (
-	errInvalidEventMessage      = "invalid event"
-	errInvalidRecurrenceMessage = "invalid recurrence"
-	errInvalidDayOfWeekMessage  = "invalid day of week"
-	errNoConflictFoundMessage   = "no conflict found for the specified date"
-	errNoRecurrenceFoundMessage = "no recurrence found for the specified day"
-	errInvalidEmailMessage      = "invalid email format"
-	errInvalidReminderMessage   = "invalid reminder"
-	errInvalidJournalMessage    = "invalid journal entry"
-	errInvalidTodoMessage       = "invalid todo component"
-	errInvalidCalendarMessage   = "invalid calendar"
+	errInvalidEventMessage        = "invalid event"
+	errInvalidRecurrenceMessage   = "invalid recurrence"
+	errInvalidDayOfWeekMessage    = "invalid day of week"
+	errNoConflictFoundMessage     = "no conflict found for the specified date"
+	errNoRecurrenceFoundMessage   = "no recurrence found for the specified day"
+	errNoOccurrenceFoundMessage   = "no occurrence found at the specified time"
+	errInvalidEmailMessage        = "invalid email format"
+	errInvalidParticipantMessage  = "invalid participant"
+	errInvalidReminderMessage     = "invalid reminder"
+	errInvalidJournalMessage      = "invalid journal entry"
+	errInvalidTodoMessage         = "invalid todo component"
+	errInvalidCalendarMessage     = "invalid calendar"
+	errInvalidAttachmentMessage   = "invalid attachment"
+	errInvalidGeoMessage          = "invalid geographic position"
+	errInvalidRelationMessage     = "invalid relation"
+	errDanglingRelationMessage    = "relation to unknown component"
+	errRelationCycleMessage       = "relation cycle"
+	errDuplicateUIDMessage        = "duplicate UID"
+	errInvalidTimeZoneMessage     = "invalid time zone"
+	errInvalidTransitionMessage   = "invalid todo status change"
+	errMalformedCalendarMessage   = "malformed calendar data"
+	errInvalidMergeOptionsMessage = "invalid merge options"
 )
 
 var (
@@ -31,9 +43,15 @@ var (
 	// ErrNoRecurrenceFound is returned when no recurrence is found for the specified day.
 	ErrNoRecurrenceFound = fmt.Errorf(errNoRecurrenceFoundMessage)
 
+	// ErrNoOccurrenceFound is returned when an event does not occur at the specified time.
+	ErrNoOccurrenceFound = fmt.Errorf(errNoOccurrenceFoundMessage)
+
 	// ErrInvalidEmail is returned when an email format is invalid.
 	ErrInvalidEmail = fmt.Errorf(errInvalidEmailMessage)
 
+	// ErrInvalidParticipant is returned when an organizer or attendee is not valid.
+	ErrInvalidParticipant = fmt.Errorf(errInvalidParticipantMessage)
+
 	// ErrInvalidReminder is returned when a reminder is not valid.
 	ErrInvalidReminder = fmt.Errorf(errInvalidReminderMessage)
 
@@ -45,9 +63,64 @@ var (
 
 	// ErrInvalidCalendar is returned when a calendar is not valid.
 	ErrInvalidCalendar = fmt.Errorf(errInvalidCalendarMessage)
+
+	// ErrInvalidAttachment is returned when an attachment is not valid.
+	ErrInvalidAttachment = fmt.Errorf(errInvalidAttachmentMessage)
+
+	// ErrInvalidGeo is returned when a latitude or longitude is out of range.
+	ErrInvalidGeo = fmt.Errorf(errInvalidGeoMessage)
+
+	// ErrInvalidRelation is returned when a RELATED-TO relation is not valid.
+	ErrInvalidRelation = fmt.Errorf(errInvalidRelationMessage)
+
+	// ErrDanglingRelation is returned when a relation refers to a UID that is not in the calendar.
+	ErrDanglingRelation = fmt.Errorf(errDanglingRelationMessage)
+
+	// ErrRelationCycle is returned when a component is its own ancestor.
+	ErrRelationCycle = fmt.Errorf(errRelationCycleMessage)
+
+	// ErrDuplicateUID is returned when components that are not occurrences of one another share a UID.
+	ErrDuplicateUID = fmt.Errorf(errDuplicateUIDMessage)
+
+	// ErrInvalidTimeZone is returned when a time zone is not known.
+	ErrInvalidTimeZone = fmt.Errorf(errInvalidTimeZoneMessage)
+
+	// ErrInvalidTodoTransition is returned when a To-Do can not change to the requested status.
+	ErrInvalidTodoTransition = fmt.Errorf(errInvalidTransitionMessage)
+
+	// ErrMalformedCalendar is returned when calendar data can not be parsed.
+	ErrMalformedCalendar = fmt.Errorf(errMalformedCalendarMessage)
+
+	// ErrInvalidMergeOptions is returned when calendars can not be merged with the MergeOptions.
+	ErrInvalidMergeOptions = fmt.Errorf(errInvalidMergeOptionsMessage)
 )
 
 // ErrEndTimeBeforeStartTime is returned when the end time is before the start time.
 func ErrEndTimeBeforeStartTime(endTime, startTime string) error {
 	return fmt.Errorf("end time '%s' is before start time '%s'", endTime, startTime)
 }
+
+// ErrDanglingRelationFor is returned when the component uid relates to the unknown component target.
+func ErrDanglingRelationFor(uid, target string) error {
+	return fmt.Errorf("%w: '%s' relates to '%s'", ErrDanglingRelation, uid, target)
+}
+
+// ErrRelationCycleAt is returned when the component uid is its own ancestor.
+func ErrRelationCycleAt(uid string) error {
+	return fmt.Errorf("%w: '%s' is its own ancestor", ErrRelationCycle, uid)
+}
+
+// ErrDuplicateUIDFor is returned when more than one component has the uid.
+func ErrDuplicateUIDFor(uid string) error {
+	return fmt.Errorf("%w: '%s'", ErrDuplicateUID, uid)
+}
+
+// ErrInvalidTodoTransitionFrom is returned when action can not be applied to a To-Do with the status.
+func ErrInvalidTodoTransitionFrom(action, status string) error {
+	return fmt.Errorf("%w: can not %s a %s To-Do", ErrInvalidTodoTransition, action, status)
+}
+
+// ErrInvalidMergeOptionsFor is returned when calendars can not be merged, for the reason.
+func ErrInvalidMergeOptionsFor(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidMergeOptions, reason)
+}
@@ -8,7 +8,22 @@ import (
 
 // iCalendar VEVENT component
 type Event struct {
+	// OPTIONAL: Persistent, globally unique identifier of the event
+	//
+	// Generated from the title and days of the event if empty
+	UID string
+
+	// OPTIONAL: Revision of the event, increased by the organizer on every significant change
+	//
+	// Written as SEQUENCE when it is not 0
+	Sequence int
+
+	// OPTIONAL: When the event was last changed, written as LAST-MODIFIED in UTC
+	LastModified *time.Time
+
 	// REQUIRED: Title of the event
+	//
+	// Events read by Parse may have none, as SUMMARY is optional in calendar data
 	Title string
 
 	// REQUIRED: Description of the event
@@ -24,8 +39,16 @@ type Event struct {
 	StartDate time.Time
 
 	// REQUIRED: End date/time of the event
+	//
+	// Not required for non-recurring events if Duration is set
 	EndDate time.Time
 
+	// OPTIONAL: Length of a non-recurring event, as an alternative to EndDate
+	//
+	// Written as DURATION instead of DTEND.
+	// Recurring events use Recurrences.Duration instead.
+	Duration *Duration
+
 	// REQUIRED: Time zone of the event
 	TimeZone TimeZone
 
@@ -39,26 +62,61 @@ type Event struct {
 
 	// OPTIONAL: List of reminders for the event
 	Reminders []Reminder
+
+	// OPTIONAL: Documents associated with the event, e.g. an agenda
+	Attachments []Attachment
+
+	// OPTIONAL: Categories of the event
+	//
+	// E.g., "MEETING", "PUBLIC HOLIDAY"
+	Categories []string
+
+	// OPTIONAL: Geographic position of the event
+	Geo *Geo
+
+	// OPTIONAL: URL with more information about the event
+	URL string
+
+	// OPTIONAL: Equipment or rooms required by the event
+	//
+	// E.g., "PROJECTOR", "CONFERENCE ROOM A"
+	Resources []string
+
+	// OPTIONAL: Contact information for the event
+	Contact string
+
+	// OPTIONAL: Comments intended for the calendar user
+	Comments []string
+
+	// OPTIONAL: Relationships to other components, by UID
+	Relations []Relation
+
+	// What the event was read from by Parse, nil for events created in code
+	source *source
 }
 
 // Generate creates the iCal formatted string for the event.
 func (e *Event) Generate() (string, error) {
-	if !e.Valid() {
-		return "", ErrInvalidEvent
+	return e.generate(generateOptions{})
+}
+
+func (e *Event) generate(opts generateOptions) (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
 	}
 
 	var builder strings.Builder
 
 	if len(e.Recurrences) > 0 {
 		// Recurring
-		for _, rec := range e.Recurrences {
-			recRule, err := rec.Generate(e.StartDate, e.EndDate, e.TimeZone)
+		for i, rec := range e.Recurrences {
+			recRule, err := rec.generate(e.StartDate, e.EndDate, e.TimeZone, opts)
 			if err != nil {
 				return "", err
 			}
 
 			builder.WriteString("BEGIN:VEVENT" + lineBreak)
-			builder.WriteString("UID:" + rec.uid() + lineBreak)
+			builder.WriteString("UID:" + e.recurrenceUID(i) + lineBreak)
 			builder.WriteString(recRule + lineBreak)
 			err = e.buildEventDetails(&builder)
 			if err != nil {
@@ -72,8 +130,12 @@ func (e *Event) Generate() (string, error) {
 		builder.WriteString("BEGIN:VEVENT" + lineBreak)
 		builder.WriteString("UID:" + e.uid() + lineBreak)
 
-		builder.WriteString(fmt.Sprintf("DTSTART;TZID=%s:%s", e.TimeZone.ID(), timeToICal(e.StartDate)) + lineBreak)
-		builder.WriteString(fmt.Sprintf("DTEND;TZID=%s:%s", e.TimeZone.ID(), timeToICal(e.EndDate)) + lineBreak)
+		builder.WriteString(fmt.Sprintf("DTSTART;TZID=%s:%s", paramValue(opts.tzid(e.TimeZone)), timeToICal(e.TimeZone.localTime(e.StartDate))) + lineBreak)
+		if e.Duration != nil {
+			builder.WriteString("DURATION:" + e.Duration.String() + lineBreak)
+		} else {
+			builder.WriteString(fmt.Sprintf("DTEND;TZID=%s:%s", paramValue(opts.tzid(e.TimeZone)), timeToICal(e.TimeZone.localTime(e.EndDate))) + lineBreak)
+		}
 		err := e.buildEventDetails(&builder)
 		if err != nil {
 			return "", err
@@ -106,7 +168,7 @@ func (e *Event) CancelOnDate(cancelDate time.Time) error {
 }
 
 func (e *Event) AddAttendee(name, email string) error {
-	if !validateEmail(email) {
+	if !validateCalAddress(email) {
 		return ErrInvalidEmail
 	}
 
@@ -115,7 +177,7 @@ func (e *Event) AddAttendee(name, email string) error {
 }
 
 func (e *Event) AddOrganizer(name, email string) error {
-	if !validateEmail(email) {
+	if !validateCalAddress(email) {
 		return ErrInvalidEmail
 	}
 
@@ -124,23 +186,84 @@ func (e *Event) AddOrganizer(name, email string) error {
 }
 
 func (e *Event) AddReminder(reminder Reminder) error {
-	if !reminder.valid() {
-		return ErrInvalidReminder
+	if err := reminder.Validate(); err != nil {
+		return err
 	}
 
 	e.Reminders = append(e.Reminders, reminder)
 	return nil
 }
 
+func (e *Event) AddAttachment(attachment Attachment) error {
+	if !attachment.valid() {
+		return ErrInvalidAttachment
+	}
+
+	e.Attachments = append(e.Attachments, attachment)
+	return nil
+}
+
+// Sets the geographic position of the event.
+//
+// Latitude must be between -90 and 90, longitude between -180 and 180.
+func (e *Event) SetGeo(latitude, longitude float64) error {
+	geo := Geo{Latitude: latitude, Longitude: longitude}
+	if !geo.valid() {
+		return ErrInvalidGeo
+	}
+
+	e.Geo = &geo
+	return nil
+}
+
+// HasCategory reports whether the event is in the given category, ignoring case.
+func (e *Event) HasCategory(category string) bool {
+	for _, c := range e.Categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// End returns the end date/time of the event.
+//
+// For non-recurring events defined by a Duration, the end is computed from the start.
+func (e *Event) End() time.Time {
+	if e.Duration != nil && !e.HasRecurrences() {
+		return e.Duration.AddTo(e.StartDate)
+	}
+	return e.EndDate
+}
+
 func (e *Event) uid() string {
+	if e.UID != "" {
+		return e.UID
+	}
 	return fmt.Sprintf("%s-%s-%s@iCal.go", strings.ReplaceAll(e.Title, " ", "_"),
-		e.StartDate.Weekday(), e.EndDate.Weekday())
+		e.StartDate.Weekday(), e.End().Weekday())
+}
+
+// UID of the VEVENT written for the recurrence at index i
+//
+// Every recurrence is written as a separate VEVENT, so each needs its own UID.
+func (e *Event) recurrenceUID(i int) string {
+	if e.UID == "" {
+		return e.Recurrences[i].uid()
+	}
+	if i == 0 {
+		return e.UID
+	}
+	return fmt.Sprintf("%s-%d", e.UID, i)
 }
 
 func (e *Event) buildEventDetails(builder *strings.Builder) error {
 	builder.WriteString(fmt.Sprintf("DTSTAMP:%s", fmt.Sprintf("%sZ", timeToICal(time.Now().UTC()))) + lineBreak)
-	builder.WriteString("SUMMARY:" + e.Title + lineBreak)
-	builder.WriteString("LOCATION:" + e.Location + lineBreak)
+	generateRevision(builder, e.Sequence, e.LastModified)
+	if e.Title != "" {
+		builder.WriteString(foldLine("SUMMARY:"+escapeText(e.Title)) + lineBreak)
+	}
+	builder.WriteString(foldLine("LOCATION:"+escapeText(e.Location)) + lineBreak)
 
 	if e.Organizer != nil {
 		err := e.Organizer.generateOrganizer(builder)
@@ -153,12 +276,45 @@ func (e *Event) buildEventDetails(builder *strings.Builder) error {
 		description := cleanDescription(e.Description)
 		builder.WriteString("DESCRIPTION:" + description + lineBreak)
 	}
+	if len(e.Categories) > 0 {
+		builder.WriteString(foldLine("CATEGORIES:"+escapeTextList(e.Categories)) + lineBreak)
+	}
+	if e.Geo != nil {
+		err := e.Geo.generate(builder)
+		if err != nil {
+			return err
+		}
+	}
+	if e.URL != "" {
+		builder.WriteString(foldLine("URL:"+e.URL) + lineBreak)
+	}
+	if len(e.Resources) > 0 {
+		builder.WriteString(foldLine("RESOURCES:"+escapeTextList(e.Resources)) + lineBreak)
+	}
+	if e.Contact != "" {
+		builder.WriteString(foldLine("CONTACT:"+escapeText(e.Contact)) + lineBreak)
+	}
+	for _, comment := range e.Comments {
+		builder.WriteString(foldLine("COMMENT:"+escapeText(comment)) + lineBreak)
+	}
 	for _, attendee := range e.Attendees {
 		err := attendee.generate(builder)
 		if err != nil {
 			return err
 		}
 	}
+	for _, attachment := range e.Attachments {
+		err := attachment.generate(builder)
+		if err != nil {
+			return err
+		}
+	}
+	for _, relation := range e.Relations {
+		err := relation.generate(builder)
+		if err != nil {
+			return err
+		}
+	}
 
 	if len(e.Reminders) > 0 {
 		for _, reminder := range e.Reminders {
@@ -199,7 +355,7 @@ func (e *Event) ConflictsWith(other *Event) (bool, time.Time) {
 
 	// Single event vs single event
 	if !e.HasRecurrences() && !other.HasRecurrences() {
-		if e.StartDate.Before(other.EndDate) && e.EndDate.After(other.StartDate) {
+		if e.StartDate.Before(other.End()) && e.End().After(other.StartDate) {
 			return true, time.Date(e.StartDate.Year(), e.StartDate.Month(), e.StartDate.Day(),
 				e.StartDate.Hour(), e.StartDate.Minute(), e.StartDate.Second(), 0, e.StartDate.Location())
 		}
@@ -225,12 +381,12 @@ func (e *Event) ConflictsWith(other *Event) (bool, time.Time) {
 			if rec.Day == singleDay {
 
 				singleStart := single.StartDate
-				singleEnd := single.EndDate
+				singleEnd := single.End()
 
 				recStart := time.Date(singleStart.Year(), singleStart.Month(), singleStart.Day(),
 					rec.StartTime.Hour(), rec.StartTime.Minute(), rec.StartTime.Second(), 0, singleStart.Location())
 				recEnd := time.Date(singleStart.Year(), singleStart.Month(), singleStart.Day(),
-					rec.EndTime.Hour(), rec.EndTime.Minute(), rec.EndTime.Second(), 0, singleStart.Location())
+					rec.endTime().Hour(), rec.endTime().Minute(), rec.endTime().Second(), 0, singleStart.Location())
 
 				if singleStart.Before(recEnd) && singleEnd.After(recStart) {
 					//Find the date of the conflict
@@ -245,40 +401,64 @@ func (e *Event) ConflictsWith(other *Event) (bool, time.Time) {
 }
 
 func (e *Event) Valid() bool {
-	if e.Title == "" {
-		return false
+	return len(e.validationErrors()) == 0
+}
+
+// Validate explains why the event is not valid, with a ValidationError for every broken rule.
+func (e *Event) Validate() error {
+	return e.validationErrors().err()
+}
+
+func (e *Event) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidEvent}
+	v.check(e.Title != "" || e.source != nil, "Title", "is required", nil)
+	if e.Duration != nil {
+		v.check(!e.HasRecurrences(), "Duration", "can not be used with Recurrences, use Recurrences.Duration", nil)
+		v.check(e.EndDate.IsZero(), "Duration", "can not be used with EndDate", nil)
+		v.check(e.Duration.positive(), "Duration", "must be positive", *e.Duration)
+	} else {
+		v.check(e.EndDate.After(e.StartDate), "EndDate", "must be after StartDate", e.EndDate)
 	}
-	if e.EndDate.Before(e.StartDate) || e.EndDate.Equal(e.StartDate) {
-		return false
+
+	v.check(e.TimeZone != "", "TimeZone", "is required", nil)
+	if e.TimeZone != "" {
+		v.checkTimeZone(e.TimeZone, "TimeZone")
 	}
+	v.check(e.Sequence >= 0, "Sequence", "can not be negative", e.Sequence)
 
-	if e.TimeZone == "" {
-		return false
+	for i := range e.Reminders {
+		v.nest(indexPath("Reminders", i), e.Reminders[i].validationErrors())
 	}
 
-	if e.Reminders != nil {
-		for _, alarm := range e.Reminders {
-			if !alarm.valid() {
-				return false
-			}
-		}
+	for i := range e.Recurrences {
+		v.nest(indexPath("Recurrences", i), e.Recurrences[i].validationErrors())
+		v.checkClocks(&e.Recurrences[i], e.TimeZone, indexPath("Recurrences", i))
 	}
 
-	if e.Recurrences != nil {
-		for _, rec := range e.Recurrences {
-			if !rec.Valid() {
-				return false
-			}
-		}
+	for i, attendee := range e.Attendees {
+		v.nest(indexPath("Attendees", i), attendee.validationErrors())
+		v.check(attendee.Status == "" || attendee.Status.validForEvent(),
+			indexPath("Attendees", i)+".Status", "can not be used on an event", attendee.Status)
 	}
 
-	if e.Attendees != nil {
-		for _, attendee := range e.Attendees {
-			if !attendee.valid() {
-				return false
-			}
-		}
+	for i := range e.Attachments {
+		v.nest(indexPath("Attachments", i), e.Attachments[i].validationErrors())
+	}
+
+	if e.Geo != nil {
+		v.nest("Geo", e.Geo.validationErrors())
+	}
+
+	for i, category := range e.Categories {
+		v.check(category != "", indexPath("Categories", i), "can not be empty", nil)
+	}
+	for i, resource := range e.Resources {
+		v.check(resource != "", indexPath("Resources", i), "can not be empty", nil)
+	}
+
+	for i := range e.Relations {
+		v.nest(indexPath("Relations", i), e.Relations[i].validationErrors())
 	}
 
-	return true
+	return v.errs
 }
@@ -1,6 +1,7 @@
 package ical
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -235,6 +236,198 @@ func TestConflictWithRecurringEvent(t *testing.T) {
 	}
 }
 
+func TestEventDetailsProperties(t *testing.T) {
+	event := mockEvent()
+	event.Categories = []string{"MEETING", "Room A, 2nd floor"}
+	event.URL = "https://example.com/events/1"
+	event.Resources = []string{"PROJECTOR", "EASEL"}
+	event.Contact = "Jim Dolittle, ABC Industries, +1-919-555-1234"
+	event.Comments = []string{"Bring your laptop; a charger too", "Lunch provided"}
+	err := event.SetGeo(37.386013, -122.082932)
+	if err != nil {
+		t.Fatalf("SetGeo() returned error: %v", err)
+	}
+
+	output, err := event.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	expected := []string{
+		"CATEGORIES:MEETING,Room A\\, 2nd floor\r\n",
+		"GEO:37.386013;-122.082932\r\n",
+		"URL:https://example.com/events/1\r\n",
+		"RESOURCES:PROJECTOR,EASEL\r\n",
+		"CONTACT:Jim Dolittle\\, ABC Industries\\, +1-919-555-1234\r\n",
+		"COMMENT:Bring your laptop\\; a charger too\r\n",
+		"COMMENT:Lunch provided\r\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Generated event missing %q", want)
+		}
+	}
+
+	cal, err := Parse(strings.NewReader(calendarData("BEGIN:VCALENDAR") + output + calendarData("END:VCALENDAR")))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if parsed := cal.Events[0]; parsed.Contact != event.Contact || !equalStrings(parsed.Comments, event.Comments) {
+		t.Errorf("Parse() Contact, Comments = %q, %q, want %q, %q", parsed.Contact, parsed.Comments, event.Contact, event.Comments)
+	}
+}
+
+func TestEventSetGeo(t *testing.T) {
+	event := mockEvent()
+	if err := event.SetGeo(91, 0); err != ErrInvalidGeo {
+		t.Errorf("SetGeo() = %v, want %v", err, ErrInvalidGeo)
+	}
+	if event.Geo != nil {
+		t.Errorf("SetGeo() set an invalid position: %+v", event.Geo)
+	}
+
+	event.Geo = &Geo{Latitude: 0, Longitude: 200}
+	if event.Valid() {
+		t.Errorf("Expected event with invalid GEO to be invalid")
+	}
+}
+
+func TestEventHasCategory(t *testing.T) {
+	event := mockEvent()
+	event.Categories = []string{"Meeting", "Public"}
+	if !event.HasCategory("MEETING") {
+		t.Errorf("Expected HasCategory(\"MEETING\") to be true")
+	}
+	if event.HasCategory("Holiday") {
+		t.Errorf("Expected HasCategory(\"Holiday\") to be false")
+	}
+
+	event.Categories = append(event.Categories, "")
+	if event.Valid() {
+		t.Errorf("Expected event with empty category to be invalid")
+	}
+}
+
+func TestEventDuration(t *testing.T) {
+	start := time.Date(2025, time.November, 17, 9, 0, 0, 0, time.UTC)
+	event := Event{
+		Title:     "Duration Event",
+		StartDate: start,
+		Duration:  &Duration{Time: 90 * time.Minute},
+		TimeZone:  TimeZone(timezones.UTC),
+	}
+	if !event.Valid() {
+		t.Fatalf("Expected event with DURATION to be valid")
+	}
+	if !event.End().Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("End() = %v, want %v", event.End(), start.Add(90*time.Minute))
+	}
+
+	output, err := event.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if !strings.Contains(output, "DURATION:PT1H30M\r\n") {
+		t.Errorf("Generated event missing DURATION: %s", output)
+	}
+	if strings.Contains(output, "DTEND") {
+		t.Errorf("Generated event should not contain DTEND when DURATION is set: %s", output)
+	}
+
+	var tests = []struct {
+		name  string
+		event Event
+	}{
+		{"both end and duration", Event{Title: "x", StartDate: start, EndDate: start.Add(time.Hour), Duration: &Duration{Time: time.Hour}, TimeZone: TimeZone(timezones.UTC)}},
+		{"zero duration", Event{Title: "x", StartDate: start, Duration: &Duration{}, TimeZone: TimeZone(timezones.UTC)}},
+		{"negative duration", Event{Title: "x", StartDate: start, Duration: &Duration{Negative: true, Days: 1}, TimeZone: TimeZone(timezones.UTC)}},
+	}
+	for _, tt := range tests {
+		if tt.event.Valid() {
+			t.Errorf("Expected %s to be invalid", tt.name)
+		}
+	}
+}
+
+func TestEventTimesInTimeZone(t *testing.T) {
+	// 15:00 UTC is 10:00 in New York during standard time
+	start := time.Date(2025, time.January, 6, 15, 0, 0, 0, time.UTC)
+	event := Event{
+		Title:     "Zoned Event",
+		StartDate: start,
+		EndDate:   start.Add(time.Hour),
+		TimeZone:  TimeZone(timezones.America_New_York),
+	}
+	output, err := event.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	for _, expected := range []string{
+		"DTSTART;TZID=America/New_York:20250106T100000\r\n",
+		"DTEND;TZID=America/New_York:20250106T110000\r\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generated event missing %q: %s", expected, output)
+		}
+	}
+
+	// The series starts and ends on the days of the instants in New York,
+	// 02:00 UTC on Tuesday is still Monday evening there.
+	// The times of day are wall-clock times in New York
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() returned error: %v", err)
+	}
+	event = Event{
+		Title:     "Zoned Recurring Event",
+		StartDate: time.Date(2025, time.January, 7, 2, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, time.January, 28, 2, 0, 0, 0, time.UTC),
+		TimeZone:  TimeZone(timezones.America_New_York),
+		Recurrences: []Recurrences{
+			{
+				Frequency:  WeeklyFrequency,
+				Day:        time.Monday,
+				StartTime:  time.Date(0, 1, 1, 18, 0, 0, 0, newYork),
+				EndTime:    time.Date(0, 1, 1, 19, 0, 0, 0, newYork),
+				Exceptions: []time.Time{time.Date(2025, time.January, 13, 23, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	output, err = event.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	for _, expected := range []string{
+		"DTSTART;TZID=America/New_York:20250106T180000\r\n",
+		"EXDATE;TZID=America/New_York:20250113T180000",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generated recurring event missing %q: %s", expected, output)
+		}
+	}
+
+	// An exception on a date excludes the occurrence starting on it
+	event.Recurrences[0].Exceptions = []time.Time{time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC)}
+	output, err = event.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if !strings.Contains(output, "EXDATE;TZID=America/New_York:20250120T180000") {
+		t.Errorf("Generated recurring event missing the EXDATE at the start of the occurrence: %s", output)
+	}
+
+	// A time of day in another zone is not a wall-clock time in New York
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatalf("LoadLocation() returned error: %v", err)
+	}
+	event.Recurrences[0].StartTime = time.Date(0, 1, 1, 18, 0, 0, 0, paris)
+	var validationErr *ValidationError
+	if err := event.Validate(); !errors.As(err, &validationErr) || validationErr.Path != "Recurrences[0].StartTime" {
+		t.Errorf("Validate() = %v, want an error at Recurrences[0].StartTime", err)
+	}
+}
+
 func mockEvent() Event {
 	startDate := time.Date(2025, time.November, 17, 9, 0, 0, 0, time.UTC)
 	return Event{
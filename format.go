@@ -22,10 +22,36 @@ func cleanDescription(desc string) string {
 	return description
 }
 
+// Escape a TEXT value, so it is read back as it is
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-3-11-text.html
 func escapeText(text string) string {
 	text = strings.ReplaceAll(text, "\\", "\\\\")
-	text = strings.ReplaceAll(text, ";", ",")
+	text = strings.ReplaceAll(text, ";", "\\;")
+	text = strings.ReplaceAll(text, ",", "\\,")
 	text = strings.ReplaceAll(text, "\r", "")
-	text = strings.ReplaceAll(text, "\n", " ")
+	text = strings.ReplaceAll(text, "\n", "\\n")
 	return text
 }
+
+// Quote a parameter value if it contains characters that are not allowed in an unquoted value
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-2-parameters.html
+func paramValue(value string) string {
+	value = strings.ReplaceAll(value, `"`, "'")
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// Escape each value and join them into a comma separated multi-value list
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-3-11-text.html
+func escapeTextList(values []string) string {
+	escaped := make([]string, 0, len(values))
+	for _, value := range values {
+		escaped = append(escaped, escapeText(value))
+	}
+	return strings.Join(escaped, ",")
+}
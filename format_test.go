@@ -1,14 +1,11 @@
 package ical
 
-import (
-	"strings"
-	"testing"
-)
+import "testing"
 
 func TestCleanDescription(t *testing.T) {
 	rawDescription := "This is a test description with especially long content, and a lot of words and special characters."
 	cleanedDescription := cleanDescription(rawDescription)
-	expectedDescription := "This is a test description with especially long content, and a \r\n lot of words and special characters."
+	expectedDescription := "This is a test description with especially long content\\, and a\r\n  lot of words and special characters."
 	if cleanedDescription != expectedDescription {
 		t.Errorf("Expected cleaned description to be:\n%s\nGot:\n%s", expectedDescription, cleanedDescription)
 	}
@@ -23,9 +20,34 @@ func TestCleanDescription(t *testing.T) {
 
 func TestEscapeText(t *testing.T) {
 	dirtyText := "This is a test; with, special\ncharacters: \\ and more."
-	expected := "This is a test, with, special characters: \\\\ and more."
+	expected := "This is a test\\; with\\, special\\ncharacters: \\\\ and more."
 	escaped := escapeText(dirtyText)
-	if !strings.EqualFold(escaped, expected) {
+	if escaped != expected {
 		t.Errorf("escapeText() = `%v` | want `%v`", escaped, expected)
 	}
 }
+
+func TestParamValue(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"John Doe", "John Doe"},
+		{"Doe, John", `"Doe, John"`},
+		{"mailto:john@example.com", `"mailto:john@example.com"`},
+		{`The "Boss"`, "The 'Boss'"},
+	}
+	for _, tt := range tests {
+		if got := paramValue(tt.input); got != tt.expected {
+			t.Errorf("paramValue(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestEscapeTextList(t *testing.T) {
+	got := escapeTextList([]string{"MEETING", "Room A, 2nd floor", "a;b", "back\\slash"})
+	expected := `MEETING,Room A\, 2nd floor,a\;b,back\\slash`
+	if got != expected {
+		t.Errorf("escapeTextList() = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,37 @@
+package ical
+
+import (
+	"strconv"
+	"strings"
+)
+
+// iCalendar GEO property
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-8-1-6-geographic-position.html
+type Geo struct {
+	// REQUIRED: Latitude in decimal degrees, between -90 and 90
+	Latitude float64
+
+	// REQUIRED: Longitude in decimal degrees, between -180 and 180
+	Longitude float64
+}
+
+func (g *Geo) generate(builder *strings.Builder) error {
+	if !g.valid() {
+		return ErrInvalidGeo
+	}
+	builder.WriteString("GEO:" + strconv.FormatFloat(g.Latitude, 'f', -1, 64) + ";" +
+		strconv.FormatFloat(g.Longitude, 'f', -1, 64) + lineBreak)
+	return nil
+}
+
+func (g *Geo) valid() bool {
+	return len(g.validationErrors()) == 0
+}
+
+func (g *Geo) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidGeo}
+	v.check(g.Latitude >= -90 && g.Latitude <= 90, "Latitude", "must be between -90 and 90", g.Latitude)
+	v.check(g.Longitude >= -180 && g.Longitude <= 180, "Longitude", "must be between -180 and 180", g.Longitude)
+	return v.errs
+}
@@ -0,0 +1,37 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateGeo(t *testing.T) {
+	geo := Geo{Latitude: 37.386013, Longitude: -122.082932}
+	var builder strings.Builder
+	err := geo.generate(&builder)
+	if err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	expected := "GEO:37.386013;-122.082932\r\n"
+	if builder.String() != expected {
+		t.Errorf("generate() = %q, want %q", builder.String(), expected)
+	}
+}
+
+func TestGeoValid(t *testing.T) {
+	var tests = []struct {
+		geo      Geo
+		expected bool
+	}{
+		{Geo{0, 0}, true},
+		{Geo{90, 180}, true},
+		{Geo{-90, -180}, true},
+		{Geo{90.1, 0}, false},
+		{Geo{0, -180.5}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.geo.valid(); got != tt.expected {
+			t.Errorf("Geo.valid() = %v, want %v for %+v", got, tt.expected, tt.geo)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package ical
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,12 +10,30 @@ import (
 // https://icalendar.org/iCalendar-RFC-5545/3-6-3-journal-component.html
 type Journal struct {
 
+	// OPTIONAL: Persistent, globally unique identifier of the journal entry
+	//
+	// Generated from the summary if empty
+	UID string
+
+	// OPTIONAL: Revision of the journal entry, increased by the organizer on every significant change
+	//
+	// Written as SEQUENCE when it is not 0
+	Sequence int
+
+	// OPTIONAL: When the journal entry was last changed, written as LAST-MODIFIED in UTC
+	LastModified *time.Time
+
 	// REQUIRED: Short summary or title of the journal entry
 	Summary string
 
 	// REQUIRED: Detailed description of the journal entry
 	Description string
 
+	// OPTIONAL: Further descriptions of the journal entry, such as one per agenda item
+	//
+	// Each is written as its own DESCRIPTION property after Description
+	Descriptions []string
+
 	// OPTIONAL: Current status of the journal entry.
 	//
 	// Possible Values: DraftJournal, FinalJournal, CancelledJournal
@@ -25,57 +42,208 @@ type Journal struct {
 	// OPTIONAL: Start date of the journal entry.
 	StartDate *time.Time
 
+	// OPTIONAL: Time zone of the start date
+	//
+	// When set, the start is written as a date-time in the zone instead of a date
+	TimeZone TimeZone
+
+	// OPTIONAL: Further dates of a recurring journal entry, such as the minutes of a series of meetings
+	//
+	// Written as RDATEs, in the same form as the start date, which is then required
+	RecurrenceDates []time.Time
+
+	// OPTIONAL: Categories of the journal entry
+	//
+	// Written as a single comma-separated CATEGORIES property
+	Categories []string
+
 	// OPTIONAL: Organizer's name and email
 	Organizer Participant
+
+	// OPTIONAL: People the journal entry is about, such as the attendees of a meeting
+	//
+	// Status may only be NeedsActionParticipation, AcceptedParticipation or DeclinedParticipation
+	Attendees []Participant
+
+	// OPTIONAL: Documents associated with the journal entry
+	Attachments []Attachment
+
+	// OPTIONAL: Relationships to other components, by UID
+	Relations []Relation
+
+	// What the journal entry was read from by Parse, nil for journal entries created in code
+	source *source
 }
 
 type JournalStatus string
 
-func (j *Journal) generate(builder *strings.Builder) error {
-	if !j.valid() {
-		return errors.New("invalid journal entry")
+func (j *Journal) generate(builder *strings.Builder, opts generateOptions) error {
+	if err := j.Validate(); err != nil {
+		return err
 	}
 	builder.WriteString("BEGIN:VJOURNAL" + lineBreak)
 
 	builder.WriteString("UID:" + j.uid() + lineBreak)
-	builder.WriteString("DTSTAMP:" + timeToICal(time.Now().UTC()) + lineBreak)
+	builder.WriteString("DTSTAMP:" + timeToICal(time.Now().UTC()) + "Z" + lineBreak)
+	generateRevision(builder, j.Sequence, j.LastModified)
 
 	if j.StartDate != nil {
-		builder.WriteString("DTSTART;VALUE=DATE:" + j.StartDate.Format("20060102") + lineBreak)
+		builder.WriteString(j.dateProperty("DTSTART", *j.StartDate, opts) + lineBreak)
+	}
+	for _, date := range j.RecurrenceDates {
+		builder.WriteString(j.dateProperty("RDATE", date, opts) + lineBreak)
 	}
 
-	builder.WriteString("SUMMARY:" + j.Summary + lineBreak)
+	builder.WriteString(foldLine("SUMMARY:"+escapeText(j.Summary)) + lineBreak)
 	builder.WriteString("DESCRIPTION:" + cleanDescription(j.Description) + lineBreak)
+	for _, description := range j.Descriptions {
+		builder.WriteString("DESCRIPTION:" + cleanDescription(description) + lineBreak)
+	}
+	if len(j.Categories) > 0 {
+		builder.WriteString(foldLine("CATEGORIES:"+escapeTextList(j.Categories)) + lineBreak)
+	}
 
 	if j.Organizer.Email != "" {
-		builder.WriteString("ORGANIZER;CN=" + j.Organizer.Name + ":mailto:" + j.Organizer.Email + lineBreak)
+		err := j.Organizer.generateOrganizer(builder)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, attendee := range j.Attendees {
+		err := attendee.generate(builder)
+		if err != nil {
+			return err
+		}
 	}
 
 	if j.Status != "" {
 		builder.WriteString("STATUS:" + string(j.Status) + lineBreak)
 	}
 
+	for _, attachment := range j.Attachments {
+		err := attachment.generate(builder)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, relation := range j.Relations {
+		err := relation.generate(builder)
+		if err != nil {
+			return err
+		}
+	}
+
 	builder.WriteString("END:VJOURNAL" + lineBreak)
 	return nil
 }
 
 func (j *Journal) valid() bool {
-	if j.Status != "" && !j.Status.valid() {
-		return false
+	return len(j.validationErrors()) == 0
+}
+
+// Validate explains why the journal entry is not valid, with a ValidationError for every broken rule.
+func (j *Journal) Validate() error {
+	return j.validationErrors().err()
+}
+
+func (j *Journal) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidJournal}
+	v.check(j.Status == "" || j.Status.valid(), "Status", "is not a known journal status", j.Status)
+	v.check(j.Summary != "", "Summary", "is required", nil)
+	v.check(j.Description != "", "Description", "is required", nil)
+	v.check(j.Sequence >= 0, "Sequence", "can not be negative", j.Sequence)
+	for i, description := range j.Descriptions {
+		v.check(description != "", indexPath("Descriptions", i), "can not be empty", nil)
 	}
 
-	if j.Summary == "" {
-		return false
+	if j.TimeZone != "" {
+		v.checkTimeZone(j.TimeZone, "TimeZone")
 	}
+	v.check(len(j.RecurrenceDates) == 0 || j.StartDate != nil, "StartDate", "is required with RecurrenceDates", nil)
 
-	if j.Description == "" {
-		return false
+	for i, category := range j.Categories {
+		v.check(category != "", indexPath("Categories", i), "can not be empty", nil)
+	}
+
+	for i, attendee := range j.Attendees {
+		v.nest(indexPath("Attendees", i), attendee.validationErrors())
+		v.check(attendee.Status == "" || attendee.Status.validForJournal(),
+			indexPath("Attendees", i)+".Status", "can not be used on a journal entry", attendee.Status)
+	}
+
+	for i := range j.Attachments {
+		v.nest(indexPath("Attachments", i), j.Attachments[i].validationErrors())
+	}
+
+	for i := range j.Relations {
+		v.nest(indexPath("Relations", i), j.Relations[i].validationErrors())
 	}
 
-	return true
+	return v.errs
+}
+
+// LinkEvent relates the journal entry to the occurrence of the event starting at occurrence,
+// such as the minutes of one meeting in a series.
+//
+// The journal entry takes the start and time zone of the occurrence when it has no start date.
+// Returns ErrNoOccurrenceFound when the event does not occur at that time.
+func (j *Journal) LinkEvent(event *Event, occurrence time.Time) error {
+	relation := Relation{Type: ParentRelation}
+	if !event.HasRecurrences() {
+		if !event.StartDate.Equal(occurrence) {
+			return ErrNoOccurrenceFound
+		}
+		relation.UID = event.uid()
+	} else {
+		startDate, endDate := event.TimeZone.localTime(event.StartDate), event.TimeZone.localTime(event.EndDate)
+		for i := range event.Recurrences {
+			rec := &event.Recurrences[i]
+			for _, start := range rec.Occurrences(startDate, endDate) {
+				if start.Equal(occurrence) && !rec.isException(start) {
+					relation.UID = event.recurrenceUID(i)
+					relation.Occurrence = start
+				}
+			}
+		}
+		if relation.UID == "" {
+			return ErrNoOccurrenceFound
+		}
+	}
+
+	j.Relations = append(j.Relations, relation)
+	if j.StartDate == nil {
+		j.StartDate = &occurrence
+		if j.TimeZone == "" {
+			j.TimeZone = event.TimeZone
+		}
+	}
+	return nil
+}
+
+// HasCategory reports whether the journal entry is in the given category, ignoring case.
+func (j *Journal) HasCategory(category string) bool {
+	for _, c := range j.Categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// The DTSTART or RDATE property for date, as a date-time in the time zone when set, else as a date
+func (j *Journal) dateProperty(name string, date time.Time, opts generateOptions) string {
+	if j.TimeZone != "" {
+		return zonedTime(name, date, j.TimeZone, opts)
+	}
+	return name + ";VALUE=DATE:" + date.Format("20060102")
 }
 
 func (j *Journal) uid() string {
+	if j.UID != "" {
+		return j.UID
+	}
 	return fmt.Sprintf("%s-%d@iCal.go", strings.ReplaceAll(j.Summary, " ", "_"), time.Now().Unix())
 }
 
@@ -1,9 +1,12 @@
 package ical
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
 )
 
 func TestJournal(t *testing.T) {
@@ -24,6 +27,24 @@ func TestJournal(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "Invalid Journal - Recurrence Dates Without Start",
+			journal: &Journal{
+				Summary:         "Recurring Journal",
+				Description:     "No start",
+				RecurrenceDates: []time.Time{time.Now()},
+			},
+			want: false,
+		},
+		{
+			name: "Invalid Journal - Attendee Status",
+			journal: &Journal{
+				Summary:     "Minutes",
+				Description: "Notes",
+				Attendees:   []Participant{{Name: "Bob", Email: "bob@example.com", Status: TentativeParticipation}},
+			},
+			want: false,
+		},
 		{
 			name: "Invalid Journal - Invalid Status",
 			journal: &Journal{
@@ -47,7 +68,7 @@ func TestGenerateJournal(t *testing.T) {
 	journal := mockJournal()
 
 	var builder strings.Builder
-	err := journal.generate(&builder)
+	err := journal.generate(&builder, generateOptions{})
 	if err != nil {
 		t.Errorf("Error generating journal: %v", err)
 	}
@@ -73,6 +94,91 @@ func TestGenerateJournal(t *testing.T) {
 	}
 }
 
+func TestGenerateJournalProperties(t *testing.T) {
+	journal := mockJournal()
+	journal.UID = "minutes@example.com"
+	journal.Descriptions = []string{"Agenda item 1", "Agenda item 2"}
+	journal.Categories = []string{"MINUTES", "TEAM"}
+	journal.RecurrenceDates = []time.Time{time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)}
+	journal.Attendees = []Participant{{Name: "Alice", Email: "alice@example.com", Status: AcceptedParticipation}}
+	journal.Sequence = 2
+	modified := time.Date(2025, 1, 2, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	journal.LastModified = &modified
+
+	var builder strings.Builder
+	if err := journal.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("Error generating journal: %v", err)
+	}
+	output := builder.String()
+	for _, expected := range []string{
+		"DTSTART;VALUE=DATE:20250101\r\n",
+		"RDATE;VALUE=DATE:20250108\r\n",
+		"DESCRIPTION:This is a test journal entry for unit testing.\r\n",
+		"DESCRIPTION:Agenda item 1\r\n",
+		"DESCRIPTION:Agenda item 2\r\n",
+		"CATEGORIES:MINUTES,TEAM\r\n",
+		"SEQUENCE:2\r\n",
+		"LAST-MODIFIED:20250102T083000Z\r\n",
+		"ATTENDEE;",
+		"mailto:alice@example.com",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generated journal missing %q: %s", expected, output)
+		}
+	}
+
+	journal.TimeZone = TimeZone(timezones.Europe_Paris)
+	journal.RecurrenceDates = []time.Time{time.Date(2025, 1, 8, 9, 0, 0, 0, time.UTC)}
+	builder.Reset()
+	if err := journal.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("Error generating journal: %v", err)
+	}
+	if !strings.Contains(builder.String(), "RDATE;TZID=Europe/Paris:20250108T100000\r\n") {
+		t.Errorf("Generated journal missing zoned RDATE: %s", builder.String())
+	}
+}
+
+func TestJournalLinkEvent(t *testing.T) {
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	meeting := Event{
+		UID:       "standup@example.com",
+		Title:     "Standup",
+		StartDate: start,
+		EndDate:   start.Add(28 * 24 * time.Hour),
+		TimeZone:  TimeZone(timezones.UTC),
+		Recurrences: []Recurrences{{
+			Frequency: WeeklyFrequency,
+			Day:       time.Monday,
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 9, 15, 0, 0, time.UTC),
+		}},
+	}
+
+	occurrence := time.Date(2025, time.January, 13, 9, 0, 0, 0, time.UTC)
+	minutes := Journal{Summary: "Standup minutes", Description: "Notes"}
+	if err := minutes.LinkEvent(&meeting, occurrence); err != nil {
+		t.Fatalf("LinkEvent() returned error: %v", err)
+	}
+	if minutes.StartDate == nil || !minutes.StartDate.Equal(occurrence) || minutes.TimeZone != meeting.TimeZone {
+		t.Errorf("LinkEvent() should take the start of the occurrence, got %v %s", minutes.StartDate, minutes.TimeZone)
+	}
+
+	var builder strings.Builder
+	if err := minutes.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("Error generating journal: %v", err)
+	}
+	expected := "RELATED-TO;RELTYPE=PARENT;X-RECURRENCE-ID=20250113T090000Z:standup@example.com\r\n"
+	if !strings.Contains(strings.ReplaceAll(builder.String(), "\r\n ", ""), expected) {
+		t.Errorf("Generated journal missing %q: %s", expected, builder.String())
+	}
+
+	missed := Journal{Summary: "Wrong day", Description: "Notes"}
+	err := missed.LinkEvent(&meeting, occurrence.Add(24*time.Hour))
+	if !errors.Is(err, ErrNoOccurrenceFound) {
+		t.Errorf("LinkEvent() = %v, want %v", err, ErrNoOccurrenceFound)
+	}
+}
+
 func TestValidJournal(t *testing.T) {
 	journal := mockJournal()
 	if !journal.valid() {
@@ -0,0 +1,344 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A problem found by Lint that may stop other applications from reading the calendar as intended
+type Finding struct {
+	// Stable ID of the rule, e.g. DuplicateUIDRule
+	Rule string
+
+	// How serious the problem is
+	Severity Severity
+
+	// Name of the component the problem is in, e.g. "VEVENT"
+	Component string
+
+	// UID of the component, when known
+	UID string
+
+	// Line of the generated calendar the problem is on, counting folded lines
+	//
+	// 0 when the problem is not on a single line
+	Line int
+
+	// Description of the problem
+	Message string
+}
+
+// How serious a Finding is
+type Severity string
+
+const (
+	// The calendar breaks a MUST of RFC 5545, applications may reject it
+	ErrorSeverity Severity = "ERROR"
+
+	// The calendar is allowed, but applications may read it differently
+	WarningSeverity Severity = "WARNING"
+)
+
+// Stable IDs of the rules checked by Lint
+const (
+	// The calendar is not valid, so nothing else was checked
+	InvalidCalendarRule = "invalid-calendar"
+
+	// A VEVENT, VTODO or VJOURNAL has no DTSTAMP
+	MissingDTSTAMPRule = "missing-dtstamp"
+
+	// A VEVENT, VTODO or VJOURNAL has no UID
+	MissingUIDRule = "missing-uid"
+
+	// A DTSTAMP is not in UTC
+	DTSTAMPNotUTCRule = "dtstamp-not-utc"
+
+	// The UNTIL of an RRULE is not of the same type as DTSTART
+	UntilTypeMismatchRule = "until-type-mismatch"
+
+	// Two components share a UID (and RECURRENCE-ID)
+	DuplicateUIDRule = "duplicate-uid"
+
+	// A line is longer than 75 octets
+	LineTooLongRule = "line-too-long"
+
+	// An EXDATE is not the start of an occurrence
+	UnmatchedExceptionRule = "unmatched-exdate"
+
+	// An EMAIL VALARM has no SUMMARY
+	EmailReminderWithoutSummaryRule = "email-valarm-without-summary"
+)
+
+func (f Finding) String() string {
+	location := f.Component
+	if f.UID != "" {
+		location += " " + f.UID
+	}
+	if f.Line > 0 {
+		location += fmt.Sprintf(" (line %d)", f.Line)
+	}
+	return fmt.Sprintf("%s %s: %s: %s", f.Severity, f.Rule, strings.TrimSpace(location), f.Message)
+}
+
+// Lint checks the calendar for interoperability problems beyond validity, such as duplicate UIDs,
+// DTSTAMPs that are not in UTC or EXDATEs that don't match the start of an occurrence.
+//
+// The calendar is generated and the output is checked, so the findings are about what other applications read.
+// An invalid calendar can not be generated, so only its validation errors are returned.
+func Lint(c *Calendar) []Finding {
+	data, err := c.Generate()
+	if err != nil {
+		return invalidFindings(err)
+	}
+
+	findings := lintOutput(string(data))
+	findings = append(findings, lintExceptions(data)...)
+	return findings
+}
+
+// A finding for every validation error
+func invalidFindings(err error) []Finding {
+	var validationErrs ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return []Finding{{Rule: InvalidCalendarRule, Severity: ErrorSeverity, Component: "VCALENDAR", Message: err.Error()}}
+	}
+	findings := make([]Finding, len(validationErrs))
+	for i, validationErr := range validationErrs {
+		findings[i] = Finding{Rule: InvalidCalendarRule, Severity: ErrorSeverity, Component: "VCALENDAR", Message: validationErr.Error()}
+	}
+	return findings
+}
+
+// A component of the generated calendar, as read back by the linter
+type lintComponent struct {
+	name       string
+	line       int
+	properties []lintProperty
+}
+
+type lintProperty struct {
+	contentLine
+	line int
+}
+
+// Returns the first property with the name
+func (c *lintComponent) property(name string) (lintProperty, bool) {
+	for _, p := range c.properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return lintProperty{}, false
+}
+
+// Checks the content lines of a generated calendar
+func lintOutput(data string) []Finding {
+	var findings []Finding
+	physical := strings.Split(strings.TrimSuffix(data, lineBreak), lineBreak)
+	for i, line := range physical {
+		if len(line) > maxLineOctets {
+			findings = append(findings, Finding{
+				Rule: LineTooLongRule, Severity: WarningSeverity, Line: i + 1,
+				Message: fmt.Sprintf("line is %d octets long, lines should be folded at %d", len(line), maxLineOctets),
+			})
+		}
+	}
+
+	var stack []*lintComponent
+	seen := make(map[string]int)
+	for i := 0; i < len(physical); i++ {
+		start := i
+		line := physical[i]
+		for i+1 < len(physical) && strings.HasPrefix(physical[i+1], " ") {
+			i++
+			line += physical[i][1:]
+		}
+		if line == "" {
+			continue
+		}
+		cl, err := parseContentLine(line)
+		if err != nil {
+			continue
+		}
+
+		switch cl.Name {
+		case "BEGIN":
+			stack = append(stack, &lintComponent{name: strings.ToUpper(cl.Value), line: start + 1})
+		case "END":
+			if len(stack) == 0 {
+				continue
+			}
+			component := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			var parent *lintComponent
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			findings = append(findings, lintComponentOutput(component, parent, seen)...)
+		default:
+			if len(stack) > 0 {
+				component := stack[len(stack)-1]
+				component.properties = append(component.properties, lintProperty{contentLine: cl, line: start + 1})
+			}
+		}
+	}
+	return findings
+}
+
+// Checks a component of a generated calendar, recording the line of its UID in seen
+//
+// Findings in a component without a UID, such as a VALARM, are reported with the UID of its parent.
+func lintComponentOutput(c, parent *lintComponent, seen map[string]int) []Finding {
+	var findings []Finding
+	uid := ""
+	if p, found := c.property("UID"); found {
+		uid = p.Value
+	} else if parent != nil {
+		p, _ := parent.property("UID")
+		uid = p.Value
+	}
+	add := func(rule string, severity Severity, line int, message string) {
+		findings = append(findings, Finding{Rule: rule, Severity: severity, Component: c.name, UID: uid, Line: line, Message: message})
+	}
+
+	switch c.name {
+	case "VEVENT", "VTODO", "VJOURNAL":
+		if uid == "" {
+			add(MissingUIDRule, ErrorSeverity, c.line, "UID is required")
+		} else {
+			key := uid
+			if p, found := c.property("RECURRENCE-ID"); found {
+				key += "\x00" + p.Value
+			}
+			if first, duplicate := seen[key]; duplicate {
+				add(DuplicateUIDRule, ErrorSeverity, c.line, fmt.Sprintf("UID is already used by the component on line %d", first))
+			} else {
+				seen[key] = c.line
+			}
+		}
+
+		if stamp, found := c.property("DTSTAMP"); !found {
+			add(MissingDTSTAMPRule, ErrorSeverity, c.line, "DTSTAMP is required")
+		} else if !strings.HasSuffix(stamp.Value, "Z") {
+			add(DTSTAMPNotUTCRule, ErrorSeverity, stamp.line, fmt.Sprintf("DTSTAMP %s must be in UTC", stamp.Value))
+		}
+
+		start, hasStart := c.property("DTSTART")
+		for _, p := range c.properties {
+			if p.Name == "RRULE" && hasStart {
+				if message := untilMismatch(start, p); message != "" {
+					add(UntilTypeMismatchRule, ErrorSeverity, p.line, message)
+				}
+			}
+		}
+	case "VALARM":
+		if action, found := c.property("ACTION"); found && action.Value == string(EmailReminderAction) {
+			if _, found := c.property("SUMMARY"); !found {
+				add(EmailReminderWithoutSummaryRule, ErrorSeverity, c.line, "an EMAIL VALARM needs a SUMMARY as the subject")
+			}
+		}
+	}
+	return findings
+}
+
+// Explains why the UNTIL of the RRULE does not match the type of DTSTART, empty when it does
+//
+// UNTIL is a date when DTSTART is, in UTC when DTSTART has a time zone, and a local time when DTSTART is floating.
+func untilMismatch(start, rrule lintProperty) string {
+	var until string
+	for _, part := range strings.Split(rrule.Value, ";") {
+		if name, value, found := strings.Cut(part, "="); found && strings.EqualFold(name, "UNTIL") {
+			until = value
+		}
+	}
+	if until == "" {
+		return ""
+	}
+
+	untilIsDate := !strings.Contains(until, "T")
+	untilIsUTC := strings.HasSuffix(until, "Z")
+	valueType, _ := start.param("VALUE")
+	_, hasTimeZone := start.param("TZID")
+	switch {
+	case strings.EqualFold(valueType, "DATE"):
+		if !untilIsDate {
+			return fmt.Sprintf("UNTIL %s must be a date, like DTSTART", until)
+		}
+	case untilIsDate:
+		return fmt.Sprintf("UNTIL %s must be a date-time, like DTSTART", until)
+	case hasTimeZone || strings.HasSuffix(start.Value, "Z"):
+		if !untilIsUTC {
+			return fmt.Sprintf("UNTIL %s must be in UTC, as DTSTART is not a local time", until)
+		}
+	case untilIsUTC:
+		return fmt.Sprintf("UNTIL %s must be a local time, like DTSTART", until)
+	}
+	return ""
+}
+
+// Checks that every EXDATE of the generated events and To-Dos is the start of an occurrence
+//
+// The EXDATEs are read as they were written, in the time zone of the series, and must match the
+// date-time an occurrence starts at: an EXDATE at midnight does not exclude an occurrence at 09:00.
+func lintExceptions(data []byte) []Finding {
+	generated, _, err := ParseLenient(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	var findings []Finding
+	unmatched := func(component, uid string, exception time.Time) {
+		findings = append(findings, Finding{
+			Rule: UnmatchedExceptionRule, Severity: WarningSeverity, Component: component, UID: uid,
+			Message: fmt.Sprintf("exception %s is not the start of an occurrence", exception.Format("2006-01-02 15:04")),
+		})
+	}
+
+	for i := range generated.Events {
+		event := &generated.Events[i]
+		startDate, endDate := event.TimeZone.localTime(event.StartDate), event.TimeZone.localTime(event.EndDate)
+		for j := range event.Recurrences {
+			rec := &event.Recurrences[j]
+			occurrences := rec.Occurrences(startDate, endDate)
+			for _, exception := range rec.Exceptions {
+				if !slices.ContainsFunc(occurrences, exception.Equal) {
+					unmatched("VEVENT", event.UID, event.TimeZone.localTime(exception))
+				}
+			}
+		}
+	}
+
+	for i := range generated.Todos {
+		todo := &generated.Todos[i]
+		if todo.Recurrence == nil {
+			continue
+		}
+		start, err := todo.seriesStart()
+		if err != nil {
+			continue
+		}
+		for _, exception := range todo.Recurrence.Exceptions {
+			if !todo.Recurrence.hasInstance(start, exception) {
+				unmatched("VTODO", todo.UID, todo.TimeZone.localTime(exception))
+			}
+		}
+	}
+	return findings
+}
+
+// Whether an instance of the series starting at start, exceptions included, starts at t
+func (r *Recurrences) hasInstance(start, t time.Time) bool {
+	for instance := start; !instance.After(t); instance = r.advance(instance) {
+		if !r.Until.IsZero() && instance.After(r.Until) {
+			return false
+		}
+		if instance.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
+)
+
+func TestLintGeneratedCalendar(t *testing.T) {
+	if findings := Lint(mockCalendar()); len(findings) != 0 {
+		t.Errorf("Lint() = %v, want no findings", findings)
+	}
+}
+
+func TestLintOutput(t *testing.T) {
+	var tests = []struct {
+		name  string
+		lines []string
+		rule  string
+		line  int
+	}{
+		{"missing uid", []string{"BEGIN:VTODO", "DTSTAMP:20250101T000000Z", "END:VTODO"}, MissingUIDRule, 1},
+		{"missing dtstamp", []string{"BEGIN:VJOURNAL", "UID:a", "END:VJOURNAL"}, MissingDTSTAMPRule, 1},
+		{"dtstamp not utc", []string{"BEGIN:VTODO", "UID:a", "DTSTAMP:20250101T000000", "END:VTODO"}, DTSTAMPNotUTCRule, 3},
+		{"duplicate uid", []string{
+			"BEGIN:VEVENT", "UID:a", "DTSTAMP:20250101T000000Z", "END:VEVENT",
+			"BEGIN:VTODO", "UID:a", "DTSTAMP:20250101T000000Z", "END:VTODO",
+		}, DuplicateUIDRule, 5},
+		{"long line", []string{"BEGIN:VJOURNAL", "UID:a", "DTSTAMP:20250101T000000Z", "SUMMARY:" + strings.Repeat("x", 70), "END:VJOURNAL"}, LineTooLongRule, 4},
+		{"date start, date-time until", []string{
+			"BEGIN:VEVENT", "UID:a", "DTSTAMP:20250101T000000Z", "DTSTART;VALUE=DATE:20250101",
+			"RRULE:FREQ=DAILY;UNTIL=20250110T000000Z", "END:VEVENT",
+		}, UntilTypeMismatchRule, 5},
+		{"zoned start, local until", []string{
+			"BEGIN:VEVENT", "UID:a", "DTSTAMP:20250101T000000Z", "DTSTART;TZID=Europe/Paris:20250101T090000",
+			"RRULE:FREQ=DAILY;UNTIL=20250110T090000", "END:VEVENT",
+		}, UntilTypeMismatchRule, 5},
+		{"floating start, utc until", []string{
+			"BEGIN:VTODO", "UID:a", "DTSTAMP:20250101T000000Z", "DTSTART:20250101T090000",
+			"RRULE:FREQ=DAILY;UNTIL=20250110T090000Z", "END:VTODO",
+		}, UntilTypeMismatchRule, 5},
+		{"email alarm without summary", []string{
+			"BEGIN:VEVENT", "UID:a", "DTSTAMP:20250101T000000Z",
+			"BEGIN:VALARM", "ACTION:EMAIL", "DESCRIPTION:Reminder", "END:VALARM", "END:VEVENT",
+		}, EmailReminderWithoutSummaryRule, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := lintOutput(strings.Join(tt.lines, lineBreak) + lineBreak)
+			if len(findings) != 1 || findings[0].Rule != tt.rule || findings[0].Line != tt.line {
+				t.Errorf("lintOutput() = %v, want %s on line %d", findings, tt.rule, tt.line)
+			}
+		})
+	}
+
+	matching := []string{
+		"BEGIN:VEVENT", "UID:a", "DTSTAMP:20250101T000000Z", "DTSTART;TZID=Europe/Paris:20250101T090000",
+		"RRULE:FREQ=DAILY;UNTIL=20250110T080000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:a", "RECURRENCE-ID;TZID=Europe/Paris:20250102T090000", "DTSTAMP:20250101T000000Z", "END:VEVENT",
+	}
+	if findings := lintOutput(strings.Join(matching, lineBreak) + lineBreak); len(findings) != 0 {
+		t.Errorf("lintOutput() = %v, want no findings", findings)
+	}
+}
+
+func TestLintEmailReminder(t *testing.T) {
+	event := mockEvent()
+	event.UID = "meeting@example.com"
+	event.Reminders = []Reminder{{
+		Description: "The meeting starts in an hour",
+		Action:      EmailReminderAction,
+		Trigger:     -time.Hour,
+		Attendees:   []Participant{{Name: "Test", Email: "test@example.com"}},
+	}}
+	cal := Create("Lint", "Lint tests")
+	cal.AddEvent(event)
+
+	findings := Lint(cal)
+	if len(findings) != 1 || findings[0].Rule != EmailReminderWithoutSummaryRule || findings[0].UID != "meeting@example.com" {
+		t.Errorf("Lint() = %v, want %s", findings, EmailReminderWithoutSummaryRule)
+	}
+
+	cal.Events[0].Reminders[0].Summary = "Meeting soon"
+	if findings := Lint(cal); len(findings) != 0 {
+		t.Errorf("Lint() = %v, want no findings", findings)
+	}
+
+	// A snoozed EMAIL reminder keeps its subject
+	reminder := &cal.Events[0].Reminders[0]
+	reminder.UID = "meeting-alarm@example.com"
+	at := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	snoozed, err := reminder.Snooze(at, at.Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("Snooze() returned error: %v", err)
+	}
+	cal.Events[0].Reminders = append(cal.Events[0].Reminders, snoozed)
+	if findings := Lint(cal); len(findings) != 0 {
+		t.Errorf("Lint() with a snoozed EMAIL reminder = %v, want no findings", findings)
+	}
+}
+
+func TestLintExceptions(t *testing.T) {
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	event := Event{
+		UID:       "standup@example.com",
+		Title:     "Standup",
+		StartDate: start,
+		EndDate:   start.Add(28 * 24 * time.Hour),
+		TimeZone:  TimeZone(timezones.UTC),
+		Recurrences: []Recurrences{{
+			Frequency: WeeklyFrequency,
+			Day:       time.Monday,
+			StartTime: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, 9, 15, 0, 0, time.UTC),
+			Exceptions: []time.Time{
+				time.Date(2025, time.January, 13, 0, 0, 0, 0, time.UTC), // written at 09:00
+				time.Date(2025, time.January, 14, 0, 0, 0, 0, time.UTC), // a Tuesday
+			},
+		}},
+	}
+	todo := *mockRecurringTodo()
+	todo.Recurrence.Exceptions = append(todo.Recurrence.Exceptions, time.Date(2025, time.January, 18, 0, 0, 0, 0, time.UTC))
+
+	cal := Create("Lint", "Lint tests")
+	cal.AddEvent(event)
+	cal.AddTodo(todo)
+
+	findings := Lint(cal)
+	if len(findings) != 2 {
+		t.Fatalf("Lint() = %v, want 2 findings", findings)
+	}
+	if findings[0].Rule != UnmatchedExceptionRule || findings[0].UID != "standup@example.com" || !strings.Contains(findings[0].Message, "2025-01-14") {
+		t.Errorf("Lint() = %v, want the event exception on 2025-01-14", findings[0])
+	}
+	if findings[1].Rule != UnmatchedExceptionRule || findings[1].UID != todo.UID || !strings.Contains(findings[1].Message, "2025-01-18") {
+		t.Errorf("Lint() = %v, want the To-Do exception on 2025-01-18", findings[1])
+	}
+}
+
+func TestLintParsedExceptions(t *testing.T) {
+	cal := roundTripCalendar(t,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"X-WR-CALNAME:Lint",
+		"X-WR-CALDESC:Lint tests",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"DTSTAMP:20250101T000000Z",
+		"SUMMARY:Standup",
+		"DTSTART;TZID=Europe/Paris:20250106T090000",
+		"DTEND;TZID=Europe/Paris:20250106T091500",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20250127T080000Z",
+		"EXDATE;TZID=Europe/Paris:20250113T000000",
+		"EXDATE;TZID=Europe/Paris:20250120T090000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+
+	// The first EXDATE is on the date of an occurrence, but excludes nothing
+	findings := Lint(cal)
+	if len(findings) != 1 {
+		t.Fatalf("Lint() = %v, want 1 finding", findings)
+	}
+	if findings[0].Rule != UnmatchedExceptionRule || findings[0].UID != "standup@example.com" || !strings.Contains(findings[0].Message, "2025-01-13 00:00") {
+		t.Errorf("Lint() = %v, want the exception at 2025-01-13 00:00", findings[0])
+	}
+}
+
+func TestLintInvalidCalendar(t *testing.T) {
+	cal := &Calendar{Name: "Invalid"}
+	findings := Lint(cal)
+	if len(findings) != 1 || findings[0].Rule != InvalidCalendarRule || findings[0].Severity != ErrorSeverity {
+		t.Errorf("Lint() = %v, want %s", findings, InvalidCalendarRule)
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	finding := Finding{Rule: LineTooLongRule, Severity: WarningSeverity, Component: "VEVENT", UID: "a@b", Line: 12, Message: "too long"}
+	expected := "WARNING line-too-long: VEVENT a@b (line 12): too long"
+	if finding.String() != expected {
+		t.Errorf("String() = %q, want %q", finding.String(), expected)
+	}
+}
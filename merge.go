@@ -0,0 +1,372 @@
+package ical
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MergePolicy chooses the version Merge keeps of a component that is in more than one calendar
+//
+// existing is the version kept so far, candidate the version of a later calendar.
+// Both are of the same type, *Event, *Todo or *Journal, and the returned component must be too.
+// It may be either of them, or a new version combining both.
+type MergePolicy func(existing, candidate Component) Component
+
+// HighestSequence keeps the version with the highest Sequence, then the one modified last,
+// then the existing one. It is the default MergePolicy.
+func HighestSequence(existing, candidate Component) Component {
+	if candidate.sequence() != existing.sequence() {
+		if candidate.sequence() > existing.sequence() {
+			return candidate
+		}
+		return existing
+	}
+	return LatestModified(existing, candidate)
+}
+
+// LatestModified keeps the version with the latest LastModified, then the existing one.
+//
+// Versions without LastModified are older than every version with one.
+func LatestModified(existing, candidate Component) Component {
+	if modifiedAfter(candidate.lastModified(), existing.lastModified()) {
+		return candidate
+	}
+	return existing
+}
+
+// SourceOrder keeps the version of the calendar that comes first.
+func SourceOrder(existing, candidate Component) Component {
+	return existing
+}
+
+// Whether a is after b, a time is after no time
+func modifiedAfter(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || a.After(*b)
+}
+
+// How Merge handles components of different calendars that have the same UID
+type UIDCollision string
+
+const (
+	// The components are versions of the same component, the MergePolicy chooses the one kept
+	DeduplicateUIDs UIDCollision = "DEDUPLICATE"
+
+	// The components are different, the UIDs of the later calendar are prefixed with its namespace
+	NamespaceUIDs UIDCollision = "NAMESPACE"
+
+	// The components are different, the UIDs of the later calendar are replaced with new, unique UIDs
+	RewriteUIDs UIDCollision = "REWRITE"
+)
+
+func (u UIDCollision) valid() bool {
+	switch u {
+	case DeduplicateUIDs, NamespaceUIDs, RewriteUIDs:
+		return true
+	}
+	return false
+}
+
+// How Merge combines calendars
+type MergeOptions struct {
+	// OPTIONAL: Chooses the version kept of a component that is in more than one calendar
+	//
+	// Possible Values: HighestSequence, LatestModified, SourceOrder, or a custom function
+	//
+	// Defaults to HighestSequence
+	Policy MergePolicy
+
+	// OPTIONAL: How components of different calendars with the same UID are handled
+	//
+	// Possible Values: DeduplicateUIDs, NamespaceUIDs, RewriteUIDs
+	//
+	// Defaults to DeduplicateUIDs
+	UIDs UIDCollision
+
+	// OPTIONAL: Namespace of each calendar, by position, for NamespaceUIDs
+	//
+	// Calendars without one use their Name
+	Namespaces []string
+}
+
+// Merge combines the calendars into one, keeping a single version of every component
+// that is in more than one of them, the one with the highest SEQUENCE.
+//
+// See MergeOptions.Merge to choose how components are kept.
+func Merge(cals ...*Calendar) (*Calendar, error) {
+	return MergeOptions{}.Merge(cals...)
+}
+
+// Merge combines the calendars into one, in order.
+//
+// Components are the same component when they have the same type, UID and RECURRENCE-ID.
+// Components without a UID are never the same component, they are all kept.
+// Every version but the one the policy keeps is dropped, unless UIDs are namespaced or rewritten:
+// then the components of a calendar whose UID is already used by an earlier calendar get a new UID,
+// and the relations of the calendar follow them.
+//
+// The name and description are those of the first calendar with a name.
+// Time zones are written once, VTIMEZONEs of parsed calendars are kept once per TZID.
+func (o MergeOptions) Merge(cals ...*Calendar) (*Calendar, error) {
+	if o.Policy == nil {
+		o.Policy = HighestSequence
+	}
+	if o.UIDs == "" {
+		o.UIDs = DeduplicateUIDs
+	}
+	if !o.UIDs.valid() {
+		return nil, ErrInvalidMergeOptionsFor(fmt.Sprintf("unknown UID collision handling '%s'", o.UIDs))
+	}
+
+	m := merger{
+		MergeOptions: o,
+		merged:       &Calendar{},
+		positions:    make(map[string]int),
+		owners:       make(map[string]int),
+		timeZones:    make(map[string]bool),
+	}
+	for i, cal := range cals {
+		if cal == nil {
+			continue
+		}
+		if err := m.add(i, cal); err != nil {
+			return nil, err
+		}
+	}
+	return m.merged, nil
+}
+
+// Combines calendars one at a time
+type merger struct {
+	MergeOptions
+	merged *Calendar
+
+	// Position of every component in its slice of the merged calendar, by key
+	positions map[string]int
+
+	// Index of the first calendar that used each UID
+	owners map[string]int
+
+	// TZIDs of the VTIMEZONEs kept from parsed calendars
+	timeZones map[string]bool
+
+	// Number of components without a UID so far
+	unnamed int
+}
+
+func (m *merger) add(index int, cal *Calendar) error {
+	if m.merged.Name == "" {
+		m.merged.Name, m.merged.Description = cal.Name, cal.Description
+		m.merged.WindowsTimeZoneNames = cal.WindowsTimeZoneNames
+	}
+
+	renamed := m.renamedUIDs(index, cal)
+	for _, component := range cal.components() {
+		if !hasUID(component) {
+			continue
+		}
+		if _, owned := m.owners[component.uid()]; !owned {
+			m.owners[component.uid()] = index
+		}
+	}
+
+	for _, component := range cal.components() {
+		component = renamedCopy(component, renamed)
+		key := m.key(component)
+		position, found := m.positions[key]
+		if !found {
+			m.positions[key] = m.append(component)
+			continue
+		}
+
+		kept := m.Policy(m.at(component, position), component)
+		if fmt.Sprintf("%T", kept) != fmt.Sprintf("%T", component) {
+			return ErrInvalidMergeOptionsFor(fmt.Sprintf("the policy kept a %T instead of a %T for '%s'", kept, component, component.uid()))
+		}
+		m.replace(position, kept)
+	}
+
+	m.addSource(cal)
+	return nil
+}
+
+// Appends the component to the merged calendar, returning its position in its slice
+func (m *merger) append(c Component) int {
+	switch component := c.(type) {
+	case *Event:
+		m.merged.Events = append(m.merged.Events, *component)
+		return len(m.merged.Events) - 1
+	case *Todo:
+		m.merged.Todos = append(m.merged.Todos, *component)
+		return len(m.merged.Todos) - 1
+	case *Journal:
+		m.merged.Journals = append(m.merged.Journals, *component)
+		return len(m.merged.Journals) - 1
+	}
+	return -1
+}
+
+// The merged component of the same type as c at the position
+func (m *merger) at(c Component, position int) Component {
+	switch c.(type) {
+	case *Event:
+		return &m.merged.Events[position]
+	case *Todo:
+		return &m.merged.Todos[position]
+	case *Journal:
+		return &m.merged.Journals[position]
+	}
+	return nil
+}
+
+// Replaces the merged component of the same type at the position
+func (m *merger) replace(position int, c Component) {
+	switch component := c.(type) {
+	case *Event:
+		m.merged.Events[position] = *component
+	case *Todo:
+		m.merged.Todos[position] = *component
+	case *Journal:
+		m.merged.Journals[position] = *component
+	}
+}
+
+// New UIDs of the components of the calendar whose UID an earlier calendar already used
+func (m *merger) renamedUIDs(index int, cal *Calendar) map[string]string {
+	if m.UIDs == DeduplicateUIDs {
+		return nil
+	}
+	renamed := make(map[string]string)
+	for _, component := range cal.components() {
+		if !hasUID(component) {
+			continue
+		}
+		uid := component.uid()
+		if owner, owned := m.owners[uid]; !owned || owner == index {
+			continue
+		}
+		if _, done := renamed[uid]; done {
+			continue
+		}
+		if m.UIDs == NamespaceUIDs {
+			renamed[uid] = m.namespace(index, cal) + "/" + uid
+		} else {
+			renamed[uid] = randomUID()
+		}
+	}
+	return renamed
+}
+
+// Namespace of the calendar at index, its Name unless one was given
+func (m *merger) namespace(index int, cal *Calendar) string {
+	namespace := cal.Name
+	if index < len(m.Namespaces) && m.Namespaces[index] != "" {
+		namespace = m.Namespaces[index]
+	}
+	if namespace == "" {
+		namespace = fmt.Sprintf("calendar-%d", index+1)
+	}
+	return strings.ReplaceAll(namespace, " ", "_")
+}
+
+// Keeps the VTIMEZONEs and unknown components of a parsed calendar
+//
+// The merged calendar keeps the properties of the first parsed calendar.
+func (m *merger) addSource(cal *Calendar) {
+	if cal.source == nil {
+		return
+	}
+	if m.merged.source == nil {
+		raw := *cal.source.raw
+		raw.Components = nil
+		m.merged.source = &source{raw: &raw, baseline: cal.source.baseline}
+	}
+	merged := m.merged.source.raw
+	for _, child := range cal.source.raw.Components {
+		if child.Name == "VTIMEZONE" {
+			tzid, _ := child.property("TZID")
+			if tzid != nil && m.timeZones[tzid.Value] {
+				continue
+			}
+			if tzid != nil {
+				m.timeZones[tzid.Value] = true
+			}
+		}
+		merged.Components = append(merged.Components, child)
+	}
+}
+
+// A copy of the component, with the UIDs it has and relates to renamed
+func renamedCopy(c Component, renamed map[string]string) Component {
+	switch component := c.(type) {
+	case *Event:
+		event := *component
+		event.UID, event.Relations = renameUIDs(event.UID, event.Relations, renamed)
+		return &event
+	case *Todo:
+		todo := *component
+		todo.UID, todo.Relations = renameUIDs(todo.UID, todo.Relations, renamed)
+		return &todo
+	case *Journal:
+		journal := *component
+		journal.UID, journal.Relations = renameUIDs(journal.UID, journal.Relations, renamed)
+		return &journal
+	}
+	return c
+}
+
+// The UID and relations after the renames, the relations are copied so the original keeps its own
+func renameUIDs(uid string, relations []Relation, renamed map[string]string) (string, []Relation) {
+	if len(renamed) == 0 {
+		return uid, relations
+	}
+	if newUID, found := renamed[uid]; found {
+		uid = newUID
+	}
+	copied := make([]Relation, len(relations))
+	for i, relation := range relations {
+		if newUID, found := renamed[relation.UID]; found {
+			relation.UID = newUID
+		}
+		copied[i] = relation
+	}
+	return uid, copied
+}
+
+// Identifies a component across calendars: its type, UID and RECURRENCE-ID
+//
+// Components without a UID are unique, their key is their position in the calendars
+func (m *merger) key(c Component) string {
+	if !hasUID(c) {
+		m.unnamed++
+		return fmt.Sprintf("%T\x00\x00%d", c, m.unnamed)
+	}
+	return fmt.Sprintf("%T\x00%s\x00%s", c, c.uid(), recurrenceID(c))
+}
+
+// Whether the UID of the component was set, rather than generated
+func hasUID(c Component) bool {
+	switch component := c.(type) {
+	case *Event:
+		return component.UID != ""
+	case *Todo:
+		return component.UID != ""
+	case *Journal:
+		return component.UID != ""
+	}
+	return false
+}
+
+// A new random UID
+func randomUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	// Version 4 UUID
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x@iCal.go", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,213 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A calendar with a To-Do for every summary, all with the UID
+func mergeCalendar(name, uid string, sequence int, modified *time.Time, summaries ...string) *Calendar {
+	cal := &Calendar{Name: name, Description: name}
+	for _, summary := range summaries {
+		cal.Todos = append(cal.Todos, Todo{UID: uid, Summary: summary, Sequence: sequence, LastModified: modified})
+	}
+	return cal
+}
+
+func TestMergePolicies(t *testing.T) {
+	earlier := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	var tests = []struct {
+		name     string
+		policy   MergePolicy
+		first    *Calendar
+		second   *Calendar
+		expected string
+	}{
+		{"highest sequence", HighestSequence, mergeCalendar("A", "x", 2, &earlier, "A"), mergeCalendar("B", "x", 1, &later, "B"), "A"},
+		{"highest sequence, later", HighestSequence, mergeCalendar("A", "x", 1, nil, "A"), mergeCalendar("B", "x", 3, nil, "B"), "B"},
+		{"highest sequence, tie modified", HighestSequence, mergeCalendar("A", "x", 1, &earlier, "A"), mergeCalendar("B", "x", 1, &later, "B"), "B"},
+		{"highest sequence, tie", HighestSequence, mergeCalendar("A", "x", 1, nil, "A"), mergeCalendar("B", "x", 1, nil, "B"), "A"},
+		{"latest modified", LatestModified, mergeCalendar("A", "x", 5, &earlier, "A"), mergeCalendar("B", "x", 1, &later, "B"), "B"},
+		{"latest modified, unset", LatestModified, mergeCalendar("A", "x", 0, &earlier, "A"), mergeCalendar("B", "x", 0, nil, "B"), "A"},
+		{"source order", SourceOrder, mergeCalendar("A", "x", 0, nil, "A"), mergeCalendar("B", "x", 9, &later, "B"), "A"},
+		{"custom", func(existing, candidate Component) Component {
+			combined := *existing.(*Todo)
+			combined.Summary += " & " + candidate.(*Todo).Summary
+			return &combined
+		}, mergeCalendar("A", "x", 0, nil, "A"), mergeCalendar("B", "x", 0, nil, "B"), "A & B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeOptions{Policy: tt.policy}.Merge(tt.first, tt.second)
+			if err != nil {
+				t.Fatalf("Merge() returned error: %v", err)
+			}
+			if len(merged.Todos) != 1 || merged.Todos[0].Summary != tt.expected {
+				t.Errorf("Merge() todos = %+v, want %s", merged.Todos, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	first := mockCalendar()
+	first.Todos[0].UID = "shared@example.com"
+	second := &Calendar{Name: "Second", Description: "Second"}
+	second.Todos = []Todo{{UID: "shared@example.com", Summary: "Newer", Sequence: 1}, {UID: "other@example.com", Summary: "Other"}}
+
+	merged, err := Merge(first, nil, second)
+	if err != nil {
+		t.Fatalf("Merge() returned error: %v", err)
+	}
+	if merged.Name != first.Name || len(merged.Events) != len(first.Events) || len(merged.Journals) != len(first.Journals) {
+		t.Errorf("Merge() = %+v, want the calendar of %s", merged, first.Name)
+	}
+	if len(merged.Todos) != 2 || merged.Todos[0].Summary != "Newer" || merged.Todos[1].UID != "other@example.com" {
+		t.Errorf("Merge() todos = %+v, want Newer in place of the first, then Other", merged.Todos)
+	}
+	if first.Todos[0].Summary == "Newer" {
+		t.Errorf("Merge() changed the first calendar")
+	}
+
+	data, err := merged.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if count := strings.Count(string(data), "BEGIN:VTIMEZONE"); count != 1 {
+		t.Errorf("Generate() wrote %d VTIMEZONEs, want 1", count)
+	}
+}
+
+func TestMergeWithoutUIDs(t *testing.T) {
+	monday := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	standup := func(start time.Time) Event {
+		return Event{Title: "Standup", StartDate: start, EndDate: start.Add(15 * time.Minute), TimeZone: "Europe/Paris"}
+	}
+	cal := &Calendar{Name: "Team", Description: "Team", Events: []Event{standup(monday), standup(monday.AddDate(0, 0, 7))}}
+	other := mergeCalendar("Other", "", 0, nil, "Write", "Write")
+
+	merged, err := MergeOptions{UIDs: NamespaceUIDs}.Merge(cal, other, other)
+	if err != nil {
+		t.Fatalf("Merge() returned error: %v", err)
+	}
+	if len(merged.Events) != 2 || len(merged.Todos) != 4 {
+		t.Errorf("Merge() = %d events and %d To-Dos, want every component without a UID kept", len(merged.Events), len(merged.Todos))
+	}
+	for _, todo := range merged.Todos {
+		if todo.UID != "" {
+			t.Errorf("Merge() gave a To-Do the UID %s, want none", todo.UID)
+		}
+	}
+}
+
+func TestMergeUIDCollisions(t *testing.T) {
+	first := mergeCalendar("Team A", "1@localhost", 0, nil, "A")
+	second := mergeCalendar("Team B", "1@localhost", 0, nil, "B")
+	second.Todos = append(second.Todos, Todo{
+		UID: "2@localhost", Summary: "Child",
+		Relations: []Relation{{UID: "1@localhost", Type: ParentRelation}},
+	})
+
+	namespaced, err := MergeOptions{UIDs: NamespaceUIDs}.Merge(first, second)
+	if err != nil {
+		t.Fatalf("Merge() returned error: %v", err)
+	}
+	uids := todoUIDs(namespaced.Todos)
+	if !equalStrings(uids, []string{"1@localhost", "Team_B/1@localhost", "2@localhost"}) {
+		t.Errorf("Merge() UIDs = %q, want the UID of Team B namespaced", uids)
+	}
+	if namespaced.Todos[2].Relations[0].UID != "Team_B/1@localhost" {
+		t.Errorf("Merge() relation = %+v, want it to follow the namespaced UID", namespaced.Todos[2].Relations[0])
+	}
+	if second.Todos[1].Relations[0].UID != "1@localhost" {
+		t.Errorf("Merge() changed the relations of the second calendar")
+	}
+
+	named, err := MergeOptions{UIDs: NamespaceUIDs, Namespaces: []string{"", "b"}}.Merge(first, second)
+	if err != nil || named.Todos[1].UID != "b/1@localhost" {
+		t.Errorf("Merge() = %v, %v, want the UID namespaced with b", named, err)
+	}
+
+	rewritten, err := MergeOptions{UIDs: RewriteUIDs}.Merge(first, second)
+	if err != nil {
+		t.Fatalf("Merge() returned error: %v", err)
+	}
+	rewrittenUID := rewritten.Todos[1].UID
+	if len(rewritten.Todos) != 3 || rewrittenUID == "1@localhost" || !strings.HasSuffix(rewrittenUID, "@iCal.go") {
+		t.Errorf("Merge() todos = %+v, want the UID of Team B rewritten", rewritten.Todos)
+	}
+	if rewritten.Todos[2].Relations[0].UID != rewrittenUID {
+		t.Errorf("Merge() relation = %+v, want it to follow the rewritten UID", rewritten.Todos[2].Relations[0])
+	}
+}
+
+func TestMergeInvalidOptions(t *testing.T) {
+	cal := mergeCalendar("A", "x", 0, nil, "A")
+	if _, err := (MergeOptions{UIDs: "RENAME"}).Merge(cal); !errors.Is(err, ErrInvalidMergeOptions) {
+		t.Errorf("Merge() error = %v, want %v", err, ErrInvalidMergeOptions)
+	}
+
+	wrongType := func(existing, candidate Component) Component {
+		return &Journal{Summary: "Not a To-Do"}
+	}
+	if _, err := (MergeOptions{Policy: wrongType}).Merge(cal, cal); !errors.Is(err, ErrInvalidMergeOptions) {
+		t.Errorf("Merge() error = %v, want %v", err, ErrInvalidMergeOptions)
+	}
+}
+
+func TestMergeParsed(t *testing.T) {
+	parse := func(uid, summary, sequence string) *Calendar {
+		return roundTripCalendar(t,
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//Example//"+summary+"//EN",
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Paris",
+			"X-SOURCE:"+summary,
+			"END:VTIMEZONE",
+			"BEGIN:X-"+summary,
+			"END:X-"+summary,
+			"BEGIN:VEVENT",
+			"UID:"+uid,
+			"SUMMARY:"+summary,
+			"SEQUENCE:"+sequence,
+			"DTSTART;TZID=Europe/Paris:20250106T090000",
+			"DTEND;TZID=Europe/Paris:20250106T100000",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:"+uid,
+			"RECURRENCE-ID;TZID=Europe/Paris:20250113T090000",
+			"SUMMARY:"+summary+" moved",
+			"DTSTART;TZID=Europe/Paris:20250113T110000",
+			"DTEND;TZID=Europe/Paris:20250113T120000",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		)
+	}
+
+	merged, err := Merge(parse("a@example.com", "FIRST", "1"), parse("a@example.com", "SECOND", "2"))
+	if err != nil {
+		t.Fatalf("Merge() returned error: %v", err)
+	}
+	if len(merged.Events) != 2 || merged.Events[0].Title != "SECOND" || merged.Events[1].Title != "FIRST moved" {
+		t.Errorf("Merge() events = %+v, want SECOND and the override of FIRST", merged.Events)
+	}
+
+	output := generateString(t, merged)
+	for _, expected := range []string{"PRODID:-//Example//FIRST//EN", "X-SOURCE:FIRST", "BEGIN:X-FIRST", "BEGIN:X-SECOND", "SUMMARY:SECOND\r\n", "SUMMARY:FIRST moved"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generate() = %s, want it to contain %q", output, expected)
+		}
+	}
+	if count := strings.Count(output, "BEGIN:VTIMEZONE"); count != 1 {
+		t.Errorf("Generate() wrote %d VTIMEZONEs, want 1", count)
+	}
+	if strings.Count(output, "BEGIN:VEVENT") != 2 {
+		t.Errorf("Generate() = %s, want 2 events", output)
+	}
+}
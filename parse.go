@@ -0,0 +1,381 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseError is a problem in calendar data, at the line and column it was found
+type ParseError struct {
+	// Line of the data, starting at 1
+	Line int
+
+	// Column of the line in bytes, starting at 1
+	Column int
+
+	// Description of the problem
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+func (e *ParseError) Unwrap() error {
+	return ErrMalformedCalendar
+}
+
+// Diagnostic is a problem in calendar data that ParseLenient repaired
+type Diagnostic struct {
+	ParseError
+
+	// How the problem was repaired, e.g. "added END:VEVENT"
+	Repair string
+}
+
+func (d Diagnostic) String() string {
+	return d.ParseError.Error() + ", " + d.Repair
+}
+
+// Parse reads calendar data, failing at the first problem with a *ParseError
+// that gives the line and column of the problem.
+//
+// Names of components, properties and parameters are case-insensitive, as in RFC 5545.
+// Properties and components the structs have no field for are kept behind the calendar and written again by Generate,
+// so generating an unchanged calendar gives the data that was read. The structs may not be valid
+// when the data uses features they can not express, ParseLenient reports those features as diagnostics.
+func Parse(r io.Reader) (*Calendar, error) {
+	p := parser{}
+	return p.parse(r)
+}
+
+// ParseLenient reads calendar data, repairing what it can: bare LF line endings, unescaped commas,
+// missing END lines, invalid dates and more. Every repair is returned as a Diagnostic.
+//
+// Lines that can not be repaired are skipped, so a best-effort Calendar is returned
+// unless no calendar is found at all, or the data can not be read.
+func ParseLenient(r io.Reader) (*Calendar, []Diagnostic, error) {
+	p := parser{lenient: true}
+	cal, err := p.parse(r)
+	return cal, p.diagnostics, err
+}
+
+// Reads calendar data, in strict or lenient mode
+type parser struct {
+	lenient     bool
+	diagnostics []Diagnostic
+
+	// Whether a bare LF line ending was reported, so it is only reported once
+	reportedLF bool
+
+	// The first problem found in strict mode
+	err error
+
+	// Time zones defined by the VTIMEZONEs of the data, by TZID
+	timeZones map[string]*time.Location
+}
+
+// A place in the data
+type position struct {
+	line, column int
+}
+
+// Reports a problem in the data at pos.
+//
+// In strict mode the problem is returned as a *ParseError, so parsing stops,
+// and the first one is kept in p.err for the decoders that carry on.
+// In lenient mode it is recorded with the repair, and nil is returned so parsing can go on.
+func (p *parser) problem(pos position, message, repair string) error {
+	parseErr := ParseError{Line: pos.line, Column: pos.column, Message: message}
+	if !p.lenient {
+		if p.err == nil {
+			p.err = &parseErr
+		}
+		return &parseErr
+	}
+	p.diagnostics = append(p.diagnostics, Diagnostic{ParseError: parseErr, Repair: repair})
+	return nil
+}
+
+// Reports valid data the structs can not express at pos, such as an RRULE with INTERVAL=2.
+//
+// Strict mode accepts the data, as it is kept and written again while the struct is unchanged.
+// In lenient mode it is recorded with what was read in its place.
+func (p *parser) unsupported(pos position, message, repair string) {
+	if p.lenient {
+		p.diagnostics = append(p.diagnostics, Diagnostic{ParseError: ParseError{Line: pos.line, Column: pos.column, Message: message}, Repair: repair})
+	}
+}
+
+func (p *parser) parse(r io.Reader) (*Calendar, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := p.readLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	calendars, err := p.buildTree(lines)
+	if err != nil {
+		return nil, err
+	}
+	p.readTimeZones(calendars)
+	return p.decodeCalendar(calendars)
+}
+
+// An unfolded content line, and where its parts are in the data
+type rawLine struct {
+	text     string
+	segments []lineSegment
+
+	// The line as it was in the data, folded and without its last line break
+	folded string
+}
+
+// A physical line of the data that is part of an unfolded content line
+type lineSegment struct {
+	// Offset of the segment in the unfolded line
+	offset int
+
+	// Position of the first byte of the segment in the data
+	start position
+}
+
+// Position in the data of the byte at offset in the unfolded line
+func (l *rawLine) position(offset int) position {
+	segment := l.segments[0]
+	for _, s := range l.segments[1:] {
+		if s.offset > offset {
+			break
+		}
+		segment = s
+	}
+	return position{line: segment.start.line, column: segment.start.column + offset - segment.offset}
+}
+
+// Splits the data into unfolded content lines, skipping empty lines
+//
+// Lines should end with CRLF, continuation lines start with a space or a tab.
+func (p *parser) readLines(data string) ([]rawLine, error) {
+	var lines []rawLine
+	physical := strings.Split(data, "\n")
+	for i, text := range physical {
+		lineNumber := i + 1
+		if i == len(physical)-1 {
+			// The data ends with a line break, or the last line has none
+			if text == "" {
+				break
+			}
+		} else if strings.HasSuffix(text, "\r") {
+			text = text[:len(text)-1]
+		} else if !p.reportedLF {
+			p.reportedLF = true
+			err := p.problem(position{lineNumber, len(text) + 1}, "line ends with LF instead of CRLF", "accepted LF line endings")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if text == "" {
+			continue
+		}
+		if (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			last := &lines[len(lines)-1]
+			last.segments = append(last.segments, lineSegment{offset: len(last.text), start: position{lineNumber, 2}})
+			last.text += text[1:]
+			last.folded += lineBreak + text
+			continue
+		}
+		lines = append(lines, rawLine{text: text, segments: []lineSegment{{offset: 0, start: position{lineNumber, 1}}}, folded: text})
+	}
+	return lines, nil
+}
+
+// A component read from the data, with its properties in the order they were read
+type rawComponent struct {
+	Name       string
+	Properties []rawProperty
+	Components []*rawComponent
+
+	// Where the BEGIN line is
+	pos position
+
+	// The BEGIN and END lines as they were in the data, empty when the END line was missing
+	begin, end string
+}
+
+// A property read from the data
+type rawProperty struct {
+	contentLine
+
+	// The unfolded line the property was read from
+	source *rawLine
+}
+
+// Position in the data of the byte at offset in the value of the property
+func (p *rawProperty) valuePosition(offset int) position {
+	return p.source.position(len(p.source.text) - len(p.Value) + offset)
+}
+
+// Returns the properties with the name
+func (c *rawComponent) properties(name string) []*rawProperty {
+	var properties []*rawProperty
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			properties = append(properties, &c.Properties[i])
+		}
+	}
+	return properties
+}
+
+// Returns the first property with the name
+func (c *rawComponent) property(name string) (*rawProperty, bool) {
+	properties := c.properties(name)
+	if len(properties) == 0 {
+		return nil, false
+	}
+	return properties[0], true
+}
+
+// The components each known component may contain. Unknown components may contain anything,
+// and anything may contain unknown components, such as vendor X-components.
+var childComponents = map[string][]string{
+	"VCALENDAR":     {"VEVENT", "VTODO", "VJOURNAL", "VFREEBUSY", "VTIMEZONE", "VAVAILABILITY"},
+	"VEVENT":        {"VALARM"},
+	"VTODO":         {"VALARM"},
+	"VJOURNAL":      {},
+	"VFREEBUSY":     {},
+	"VALARM":        {},
+	"VTIMEZONE":     {"STANDARD", "DAYLIGHT"},
+	"STANDARD":      {},
+	"DAYLIGHT":      {},
+	"VAVAILABILITY": {"AVAILABLE"},
+	"AVAILABLE":     {},
+}
+
+// Whether the component may contain the child component
+func allowsChild(component, child string) bool {
+	children, known := childComponents[component]
+	if !known {
+		return true
+	}
+	if _, childKnown := childComponents[child]; !childKnown {
+		return true
+	}
+	for _, c := range children {
+		if c == child {
+			return true
+		}
+	}
+	return false
+}
+
+// Builds the component tree from BEGIN and END lines, returning the VCALENDAR components
+//
+// Lenient mode adds missing END lines. Components outside of a VCALENDAR are put into one.
+func (p *parser) buildTree(lines []rawLine) ([]*rawComponent, error) {
+	root := &rawComponent{}
+	stack := []*rawComponent{root}
+
+	// Closes the innermost open component
+	closeComponent := func() {
+		component := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		parent := stack[len(stack)-1]
+		parent.Components = append(parent.Components, component)
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		cl, err := parseContentLine(line.text)
+		if err != nil {
+			if err := p.problem(line.position(0), err.Error(), "skipped the line"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch cl.Name {
+		case "BEGIN":
+			name := strings.ToUpper(cl.Value)
+			for len(stack) > 1 && !allowsChild(stack[len(stack)-1].Name, name) {
+				open := stack[len(stack)-1].Name
+				err := p.problem(line.position(0), fmt.Sprintf("BEGIN:%s in %s, missing END:%s", name, open, open), "added END:"+open)
+				if err != nil {
+					return nil, err
+				}
+				closeComponent()
+			}
+			stack = append(stack, &rawComponent{Name: name, pos: line.position(0), begin: line.folded})
+		case "END":
+			name := strings.ToUpper(cl.Value)
+			open := -1
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].Name == name {
+					open = j
+					break
+				}
+			}
+			if open < 0 {
+				if err := p.problem(line.position(0), fmt.Sprintf("END:%s without BEGIN:%s", name, name), "skipped the line"); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for len(stack)-1 > open {
+				inner := stack[len(stack)-1].Name
+				if err := p.problem(line.position(0), "missing END:"+inner, "added END:"+inner); err != nil {
+					return nil, err
+				}
+				closeComponent()
+			}
+			closeComponent()
+		default:
+			if len(stack) == 1 {
+				if err := p.problem(line.position(0), cl.Name+" outside of a component", "skipped the line"); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			component := stack[len(stack)-1]
+			component.Properties = append(component.Properties, rawProperty{contentLine: cl, source: line})
+		}
+	}
+
+	end := position{line: 1, column: 1}
+	if len(lines) > 0 {
+		last := &lines[len(lines)-1]
+		end = last.position(len(last.text))
+	}
+	for len(stack) > 1 {
+		open := stack[len(stack)-1].Name
+		if err := p.problem(end, "missing END:"+open, "added END:"+open); err != nil {
+			return nil, err
+		}
+		closeComponent()
+	}
+
+	var calendars []*rawComponent
+	var outside []*rawComponent
+	for _, component := range root.Components {
+		if component.Name == "VCALENDAR" {
+			calendars = append(calendars, component)
+		} else {
+			outside = append(outside, component)
+		}
+	}
+	if len(outside) > 0 {
+		err := p.problem(outside[0].pos, "BEGIN:"+outside[0].Name+" outside of a VCALENDAR", "read it as part of the calendar")
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, &rawComponent{Name: "VCALENDAR", Components: outside, pos: outside[0].pos})
+	}
+	if len(calendars) == 0 {
+		return nil, &ParseError{Line: end.line, Column: end.column, Message: "no VCALENDAR found"}
+	}
+	return calendars, nil
+}
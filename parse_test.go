@@ -0,0 +1,238 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Joins the lines with CRLF line breaks
+func calendarData(lines ...string) string {
+	return strings.Join(lines, lineBreak) + lineBreak
+}
+
+func TestParseErrors(t *testing.T) {
+	var tests = []struct {
+		name    string
+		data    string
+		line    int
+		column  int
+		message string
+	}{
+		{"bare LF", "BEGIN:VCALENDAR\nEND:VCALENDAR\n", 1, 16, "line ends with LF instead of CRLF"},
+		{"unescaped comma", calendarData("BEGIN:VCALENDAR", "BEGIN:VEVENT", "SUMMARY:Lunch, with Bob", "END:VEVENT", "END:VCALENDAR"), 3, 14, `unescaped ',' in SUMMARY`},
+		{"missing end", calendarData("BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:a", "BEGIN:VTODO", "END:VTODO", "END:VCALENDAR"), 4, 1, "BEGIN:VTODO in VEVENT, missing END:VEVENT"},
+		{"missing end at eof", calendarData("BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:a"), 3, 6, "missing END:VEVENT"},
+		{"end without begin", calendarData("BEGIN:VCALENDAR", "END:VEVENT", "END:VCALENDAR"), 2, 1, "END:VEVENT without BEGIN:VEVENT"},
+		{"invalid date", calendarData("BEGIN:VCALENDAR", "BEGIN:VEVENT", "DTSTART:2025-01-06T09:00:00", "END:VEVENT", "END:VCALENDAR"), 3, 9, `invalid DTSTART value "2025-01-06T09:00:00"`},
+		{"unknown time zone", calendarData("BEGIN:VCALENDAR", "BEGIN:VEVENT", "DTSTART;TZID=Mars/Olympus:20250106T090000", "END:VEVENT", "END:VCALENDAR"), 3, 1, `unknown time zone "Mars/Olympus"`},
+		{"folded value", calendarData("BEGIN:VCALENDAR", "BEGIN:VEVENT", "DESCRIPTION:First line", " and second; line", "END:VEVENT", "END:VCALENDAR"), 4, 12, `unescaped ';' in DESCRIPTION`},
+		{"invalid line", calendarData("BEGIN:VCALENDAR", "NOT A PROPERTY", "END:VCALENDAR"), 2, 1, ""},
+		{"outside of a calendar", calendarData("BEGIN:VEVENT", "UID:a", "END:VEVENT"), 1, 1, "BEGIN:VEVENT outside of a VCALENDAR"},
+		{"empty", "", 1, 1, "no VCALENDAR found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal, err := Parse(strings.NewReader(tt.data))
+			if cal != nil {
+				t.Errorf("Parse() = %+v, want nil", cal)
+			}
+			if !errors.Is(err, ErrMalformedCalendar) {
+				t.Fatalf("Parse() error = %v, want %v", err, ErrMalformedCalendar)
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse() error = %v, want a *ParseError", err)
+			}
+			if parseErr.Line != tt.line || parseErr.Column != tt.column {
+				t.Errorf("Parse() error at line %d, column %d, want line %d, column %d", parseErr.Line, parseErr.Column, tt.line, tt.column)
+			}
+			if tt.message != "" && parseErr.Message != tt.message {
+				t.Errorf("Parse() error message = %q, want %q", parseErr.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"X-WR-CALNAME:Team",
+		"BEGIN:VEVENT",
+		"UID:lunch@example.com",
+		"DTSTART:2025-01-06T12:00:00",
+		"DTEND;TZID=Mars/Olympus:20250106T130000",
+		"SUMMARY:Lunch, with Bob",
+		"BEGIN:VTODO",
+		"UID:todo@example.com",
+		"SUMMARY:Book a table",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\n") + "\n"
+
+	cal, diagnostics, err := ParseLenient(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseLenient() returned error: %v", err)
+	}
+
+	expected := []string{
+		"line 1, column 16: line ends with LF instead of CRLF, accepted LF line endings",
+		"line 8, column 1: BEGIN:VTODO in VEVENT, missing END:VEVENT, added END:VEVENT",
+		`line 5, column 9: invalid DTSTART value "2025-01-06T12:00:00", read it as 20250106T120000`,
+		`line 6, column 1: unknown time zone "Mars/Olympus", read the time as UTC`,
+		`line 7, column 14: unescaped ',' in SUMMARY, read it as text`,
+	}
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, d.String())
+	}
+	if !equalStrings(got, expected) {
+		t.Errorf("ParseLenient() diagnostics =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+
+	if cal.Name != "Team" {
+		t.Errorf("Name = %q, want Team", cal.Name)
+	}
+	if len(cal.Events) != 1 || len(cal.Todos) != 1 {
+		t.Fatalf("ParseLenient() = %d events and %d todos, want 1 of each", len(cal.Events), len(cal.Todos))
+	}
+	event := cal.Events[0]
+	if event.Title != "Lunch, with Bob" || event.StartDate.Hour() != 12 || event.EndDate.Sub(event.StartDate).Hours() != 1 {
+		t.Errorf("ParseLenient() event = %+v, want the repaired lunch", event)
+	}
+	if cal.Todos[0].Summary != "Book a table" {
+		t.Errorf("ParseLenient() todo = %+v, want the todo after the missing END", cal.Todos[0])
+	}
+}
+
+func TestParseLenientUnrepairable(t *testing.T) {
+	data := calendarData(
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:a",
+		"SUMMARY:Kept",
+		"PRIORITY:high",
+		"DTSTART:sometime",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	cal, diagnostics, err := ParseLenient(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseLenient() returned error: %v", err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Repair != "skipped the value" || diagnostics[0].Line != 6 {
+		t.Errorf("ParseLenient() diagnostics = %v, want the skipped DTSTART", diagnostics)
+	}
+	if len(cal.Events) != 1 || cal.Events[0].Title != "Kept" || !cal.Events[0].StartDate.IsZero() {
+		t.Errorf("ParseLenient() events = %+v, want the event without a start", cal.Events)
+	}
+
+	if _, _, err := ParseLenient(strings.NewReader("not a calendar")); !errors.Is(err, ErrMalformedCalendar) {
+		t.Errorf("ParseLenient() error = %v, want %v", err, ErrMalformedCalendar)
+	}
+}
+
+func TestParseCaseInsensitive(t *testing.T) {
+	data := calendarData(
+		"begin:vcalendar",
+		"Begin:VEvent",
+		"uid:a@example.com",
+		"Summary:Mixed case",
+		"dtstart;tzid=Europe/Paris;value=date-time:20250106T090000",
+		"dtend;TzId=Europe/Paris:20250106T100000",
+		"end:vevent",
+		"END:VCALENDAR",
+	)
+	cal, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if len(cal.Events) != 1 {
+		t.Fatalf("Parse() = %d events, want 1", len(cal.Events))
+	}
+	event := cal.Events[0]
+	if event.UID != "a@example.com" || event.Title != "Mixed case" || event.TimeZone != "Europe/Paris" {
+		t.Errorf("Parse() event = %+v, want the mixed case event", event)
+	}
+}
+
+func TestParseMultipleCalendars(t *testing.T) {
+	data := calendarData(
+		"BEGIN:VCALENDAR", "BEGIN:VTODO", "UID:a", "SUMMARY:A", "END:VTODO", "END:VCALENDAR",
+		"BEGIN:VCALENDAR", "BEGIN:VTODO", "UID:b", "SUMMARY:B", "END:VTODO", "END:VCALENDAR",
+	)
+	cal, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if len(cal.Todos) != 2 || cal.Todos[0].UID != "a" || cal.Todos[1].UID != "b" {
+		t.Errorf("Parse() todos = %+v, want a and b", cal.Todos)
+	}
+}
+
+func TestParseGenerated(t *testing.T) {
+	original := mockCalendar()
+	data, err := original.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	cal, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if len(cal.Events) != len(original.Events) || len(cal.Todos) != len(original.Todos) || len(cal.Journals) != len(original.Journals) {
+		t.Fatalf("Parse() = %d events, %d todos, %d journals, want %d, %d, %d",
+			len(cal.Events), len(cal.Todos), len(cal.Journals), len(original.Events), len(original.Todos), len(original.Journals))
+	}
+
+	// The calendar name is not generated, everything else must generate again
+	cal.Name = original.Name
+	if err := cal.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	for i, event := range cal.Events {
+		if event.Title != original.Events[i].Title || event.TimeZone != original.Events[i].TimeZone || event.StartDate.IsZero() {
+			t.Errorf("Parse() event %d = %+v, want %+v", i, event, original.Events[i])
+		}
+	}
+}
+
+func TestParseGeneratedText(t *testing.T) {
+	original := mockCalendar()
+	event := &original.Events[0]
+	event.Title = "Lunch, with Bob; maybe"
+	event.Location = "Room A, 2nd floor"
+	event.Description = "Hello, world; bring snacks.\nThe room is booked at the end of the hall, next to the kitchen."
+	event.Contact = "Jim, ABC Industries; +1-919-555-1234"
+	event.Comments = []string{"Bring your laptop; a charger too", "Back\\slash, comma"}
+	original.Todos[0].Summary = "Call Jim, ABC"
+	original.Todos[0].Description = "Ask about the quote; and the invoice"
+	original.Journals[0].Summary = "Notes, 1; 2"
+
+	data, err := original.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	cal, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	parsed := cal.Events[0]
+	if parsed.Title != event.Title || parsed.Location != event.Location || parsed.Description != event.Description || parsed.Contact != event.Contact {
+		t.Errorf("Parse() event = %q, %q, %q, %q, want %q, %q, %q, %q",
+			parsed.Title, parsed.Location, parsed.Description, parsed.Contact, event.Title, event.Location, event.Description, event.Contact)
+	}
+	if !equalStrings(parsed.Comments, event.Comments) {
+		t.Errorf("Parse() Comments = %q, want %q", parsed.Comments, event.Comments)
+	}
+	if todo := cal.Todos[0]; todo.Summary != original.Todos[0].Summary || todo.Description != original.Todos[0].Description {
+		t.Errorf("Parse() todo = %q, %q, want %q, %q", todo.Summary, todo.Description, original.Todos[0].Summary, original.Todos[0].Description)
+	}
+	if journal := cal.Journals[0]; journal.Summary != original.Journals[0].Summary {
+		t.Errorf("Parse() journal Summary = %q, want %q", journal.Summary, original.Journals[0].Summary)
+	}
+}
@@ -5,37 +5,269 @@ import (
 	"strings"
 )
 
+// iCalendar calendar user, written as an ORGANIZER or ATTENDEE property
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-8-4-1-attendee.html
 type Participant struct {
 	// REQUIRED: Name of the participant
 	Name string
 
 	// REQUIRED: Email of the participant
+	//
+	// Any other calendar user address URI is accepted too,
+	// e.g. "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6" or "sip:room1@example.com"
 	Email string
+
+	// OPTIONAL: Kind of calendar user
+	//
+	// Possible Values: IndividualUser, GroupUser, ResourceUser, RoomUser, UnknownUser
+	//
+	// Defaults to IndividualUser
+	Type CalendarUserType
+
+	// OPTIONAL: Participation role of an attendee
+	//
+	// Possible Values: ChairRole, RequiredRole, OptionalRole, NonParticipantRole
+	//
+	// Defaults to RequiredRole
+	Role ParticipantRole
+
+	// OPTIONAL: Participation status of an attendee
+	//
+	// Possible Values: NeedsActionParticipation, AcceptedParticipation, DeclinedParticipation,
+	// TentativeParticipation, DelegatedParticipation, CompletedParticipation, InProcessParticipation
+	//
+	// Defaults to NeedsActionParticipation
+	Status ParticipationStatus
+
+	// OPTIONAL: Whether a reply is expected from the attendee
+	//
+	// Defaults to true
+	RSVP *bool
+
+	// OPTIONAL: Emails or addresses of the calendar users the attendee delegated participation to
+	DelegatedTo []string
+
+	// OPTIONAL: Emails or addresses of the calendar users that delegated participation to the attendee
+	DelegatedFrom []string
+
+	// OPTIONAL: Email of the calendar user acting on behalf of the participant, e.g. an assistant
+	SentBy string
+
+	// OPTIONAL: URI of a directory entry for the participant
+	//
+	// E.g., "ldap://example.com:6666/o=ABC%20Industries,c=US???(cn=Jim%20Dolittle)"
+	//
+	// Written quoted in the DIR parameter, so it can not contain quotes
+	Directory string
+
+	// OPTIONAL: Language of the participant's name, e.g. "en-US"
+	Language string
+
+	// OPTIONAL: Emails of the groups the attendee is a member of
+	Members []string
 }
 
+// The kind of calendar user (CUTYPE)
+type CalendarUserType string
+
+const (
+	IndividualUser CalendarUserType = "INDIVIDUAL"
+	GroupUser      CalendarUserType = "GROUP"
+	ResourceUser   CalendarUserType = "RESOURCE"
+	RoomUser       CalendarUserType = "ROOM"
+	UnknownUser    CalendarUserType = "UNKNOWN"
+)
+
+// The participation role of an attendee (ROLE)
+type ParticipantRole string
+
+const (
+	ChairRole          ParticipantRole = "CHAIR"
+	RequiredRole       ParticipantRole = "REQ-PARTICIPANT"
+	OptionalRole       ParticipantRole = "OPT-PARTICIPANT"
+	NonParticipantRole ParticipantRole = "NON-PARTICIPANT"
+)
+
+// The participation status of an attendee (PARTSTAT)
+type ParticipationStatus string
+
+const (
+	NeedsActionParticipation ParticipationStatus = "NEEDS-ACTION"
+	AcceptedParticipation    ParticipationStatus = "ACCEPTED"
+	DeclinedParticipation    ParticipationStatus = "DECLINED"
+	TentativeParticipation   ParticipationStatus = "TENTATIVE"
+	DelegatedParticipation   ParticipationStatus = "DELEGATED"
+
+	// Only for VTODO attendees
+	CompletedParticipation ParticipationStatus = "COMPLETED"
+
+	// Only for VTODO attendees
+	InProcessParticipation ParticipationStatus = "IN-PROCESS"
+)
+
+// Generate the ATTENDEE property of an event
 func (p *Participant) generate(builder *strings.Builder) error {
 	if !p.valid() {
 		return ErrInvalidEmail
 	}
-	builder.WriteString(fmt.Sprintf("ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE;CN=%s;X-NUM-GUESTS=0:mailto:%s", p.Name, p.Email) + lineBreak)
+
+	rsvp := true
+	if p.RSVP != nil {
+		rsvp = *p.RSVP
+	}
+
+	params := []string{
+		"CUTYPE=" + string(p.userType()),
+		"ROLE=" + string(p.role()),
+		"PARTSTAT=" + string(p.status()),
+		"RSVP=" + strings.ToUpper(fmt.Sprintf("%t", rsvp)),
+		"CN=" + paramValue(p.Name),
+	}
+	if len(p.DelegatedTo) > 0 {
+		params = append(params, "DELEGATED-TO="+calAddressList(p.DelegatedTo))
+	}
+	if len(p.DelegatedFrom) > 0 {
+		params = append(params, "DELEGATED-FROM="+calAddressList(p.DelegatedFrom))
+	}
+	if len(p.Members) > 0 {
+		params = append(params, "MEMBER="+calAddressList(p.Members))
+	}
+	params = append(params, p.commonParams()...)
+	params = append(params, "X-NUM-GUESTS=0")
+
+	p.writeProperty(builder, "ATTENDEE", params)
 	return nil
 }
 
+// Generate the ORGANIZER property
 func (p *Participant) generateOrganizer(builder *strings.Builder) error {
 	if !p.valid() {
 		return ErrInvalidEmail
 	}
-	builder.WriteString(fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", p.Name, p.Email) + lineBreak)
+
+	params := append([]string{"CN=" + paramValue(p.Name)}, p.commonParams()...)
+	p.writeProperty(builder, "ORGANIZER", params)
+	return nil
+}
+
+// Generate the ATTENDEE property of an EMAIL reminder, the recipient of the email
+func (p *Participant) generateRecipient(builder *strings.Builder) error {
+	if !p.valid() {
+		return ErrInvalidEmail
+	}
+
+	params := append([]string{"CN=" + paramValue(p.Name)}, p.commonParams()...)
+	p.writeProperty(builder, "ATTENDEE", params)
 	return nil
 }
 
+// Parameters allowed on every calendar user property
+func (p *Participant) commonParams() []string {
+	var params []string
+	if p.SentBy != "" {
+		params = append(params, "SENT-BY="+calAddressList([]string{p.SentBy}))
+	}
+	if p.Directory != "" {
+		params = append(params, `DIR="`+p.Directory+`"`)
+	}
+	if p.Language != "" {
+		params = append(params, "LANGUAGE="+paramValue(p.Language))
+	}
+	return params
+}
+
+func (p *Participant) writeProperty(builder *strings.Builder, name string, params []string) {
+	line := name + ";" + strings.Join(params, ";") + ":" + calAddress(p.Email)
+	builder.WriteString(foldLine(line) + lineBreak)
+}
+
+func (p *Participant) userType() CalendarUserType {
+	if p.Type == "" {
+		return IndividualUser
+	}
+	return p.Type
+}
+
+func (p *Participant) role() ParticipantRole {
+	if p.Role == "" {
+		return RequiredRole
+	}
+	return p.Role
+}
+
+func (p *Participant) status() ParticipationStatus {
+	if p.Status == "" {
+		return NeedsActionParticipation
+	}
+	return p.Status
+}
+
 func (p *Participant) valid() bool {
-	return validateEmail(p.Email) && p.Name != ""
+	return len(p.validationErrors()) == 0
+}
+
+func (p *Participant) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidParticipant}
+	v.checkErr(validateCalAddress(p.Email), ErrInvalidEmail, "Email", "is not a valid calendar address", p.Email)
+	v.check(p.Name != "", "Name", "is required", nil)
+	v.check(p.Type == "" || p.Type.valid(), "Type", "is not a known calendar user type", p.Type)
+	v.check(p.Role == "" || p.Role.valid(), "Role", "is not a known role", p.Role)
+	v.check(p.Status == "" || p.Status.valid(), "Status", "is not a known participation status", p.Status)
+	v.checkErr(p.SentBy == "" || validateCalAddress(p.SentBy), ErrInvalidEmail, "SentBy", "is not a valid calendar address", p.SentBy)
+	for _, list := range []struct {
+		field     string
+		addresses []string
+	}{{"DelegatedTo", p.DelegatedTo}, {"DelegatedFrom", p.DelegatedFrom}, {"Members", p.Members}} {
+		for i, address := range list.addresses {
+			v.checkErr(validateCalAddress(address), ErrInvalidEmail, indexPath(list.field, i), "is not a valid calendar address", address)
+		}
+	}
+	v.check(p.Status != DelegatedParticipation || len(p.DelegatedTo) > 0, "DelegatedTo", "is required when Status is DELEGATED", nil)
+	v.check(p.Directory == "" || strings.Contains(p.Directory, ":"), "Directory", "must be a URI", p.Directory)
+	v.check(!strings.ContainsAny(p.Directory, "\"\r\n"), "Directory", "can not contain quotes or line breaks", p.Directory)
+	return v.errs
+}
+
+func (t *CalendarUserType) valid() bool {
+	switch *t {
+	case IndividualUser, GroupUser, ResourceUser, RoomUser, UnknownUser:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *ParticipantRole) valid() bool {
+	switch *r {
+	case ChairRole, RequiredRole, OptionalRole, NonParticipantRole:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *ParticipationStatus) valid() bool {
+	switch *s {
+	case NeedsActionParticipation, AcceptedParticipation, DeclinedParticipation,
+		TentativeParticipation, DelegatedParticipation, CompletedParticipation, InProcessParticipation:
+		return true
+	default:
+		return false
+	}
+}
+
+// Whether the status may be used on a VEVENT attendee
+func (s *ParticipationStatus) validForEvent() bool {
+	return s.valid() && *s != CompletedParticipation && *s != InProcessParticipation
 }
 
-func validateEmail(email string) bool {
-	if !strings.Contains(email, "@") || strings.HasPrefix(email, "@") || strings.HasSuffix(email, "@") {
+// Whether the status may be used on a VJOURNAL attendee
+func (s *ParticipationStatus) validForJournal() bool {
+	switch *s {
+	case NeedsActionParticipation, AcceptedParticipation, DeclinedParticipation:
+		return true
+	default:
 		return false
 	}
-	return true
 }
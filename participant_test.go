@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestEventGeneration(t *testing.T) {
@@ -65,3 +66,138 @@ func TestGenerateOrganizer(t *testing.T) {
 		t.Errorf("Expected organizer %s with email %s to be in event details, but it was not found", name, email)
 	}
 }
+
+func TestGenerateAttendeeDefaults(t *testing.T) {
+	var builder strings.Builder
+	p := Participant{Name: "Jane Doe", Email: "jane@example.com"}
+	err := p.generate(&builder)
+	if err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+
+	expected := "ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE;CN=Jane Doe;X-NUM-GUESTS=0:mailto:jane@example.com"
+	if got := unfoldLines(builder.String()); len(got) != 1 || got[0] != expected {
+		t.Errorf("generate() = %q, want %q", got, expected)
+	}
+}
+
+func TestGenerateAttendeeParameters(t *testing.T) {
+	rsvp := false
+	p := Participant{
+		Name:          "Doe, John",
+		Email:         "john@example.com",
+		Type:          RoomUser,
+		Role:          OptionalRole,
+		Status:        DelegatedParticipation,
+		RSVP:          &rsvp,
+		DelegatedTo:   []string{"jane@example.com"},
+		DelegatedFrom: []string{"boss@example.com"},
+		SentBy:        "assistant@example.com",
+		Directory:     "ldap://example.com:6666/o=ABC%20Industries",
+		Language:      "en-US",
+		Members:       []string{"team@example.com", "staff@example.com"},
+	}
+
+	var builder strings.Builder
+	err := p.generate(&builder)
+	if err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	unfolded := strings.Join(unfoldLines(builder.String()), "")
+
+	expected := []string{
+		"CUTYPE=ROOM",
+		"ROLE=OPT-PARTICIPANT",
+		"PARTSTAT=DELEGATED",
+		"RSVP=FALSE",
+		`CN="Doe, John"`,
+		`DELEGATED-TO="mailto:jane@example.com"`,
+		`DELEGATED-FROM="mailto:boss@example.com"`,
+		`MEMBER="mailto:team@example.com","mailto:staff@example.com"`,
+		`SENT-BY="mailto:assistant@example.com"`,
+		`DIR="ldap://example.com:6666/o=ABC%20Industries"`,
+		"LANGUAGE=en-US",
+	}
+	for _, want := range expected {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("Generated attendee missing %s: %s", want, unfolded)
+		}
+	}
+
+	cl, err := parseContentLine(unfolded)
+	if err != nil {
+		t.Fatalf("Generated attendee is not a valid content line: %v", err)
+	}
+	if cl.Value != "mailto:john@example.com" {
+		t.Errorf("Attendee value = %q, want mailto:john@example.com", cl.Value)
+	}
+}
+
+func TestGenerateOrganizerParameters(t *testing.T) {
+	p := Participant{Name: "Boss", Email: "boss@example.com", SentBy: "assistant@example.com", Role: ChairRole}
+	var builder strings.Builder
+	err := p.generateOrganizer(&builder)
+	if err != nil {
+		t.Fatalf("generateOrganizer() returned error: %v", err)
+	}
+	expected := `ORGANIZER;CN=Boss;SENT-BY="mailto:assistant@example.com":mailto:boss@example.com`
+	if got := unfoldLines(builder.String()); len(got) != 1 || got[0] != expected {
+		t.Errorf("generateOrganizer() = %q, want %q", got, expected)
+	}
+}
+
+func TestParticipantValid(t *testing.T) {
+	var tests = []struct {
+		name        string
+		participant Participant
+		expected    bool
+	}{
+		{"defaults", Participant{Name: "A", Email: "a@example.com"}, true},
+		{"room", Participant{Name: "Room 1", Email: "room1@example.com", Type: RoomUser, Role: NonParticipantRole}, true},
+		{"invalid type", Participant{Name: "A", Email: "a@example.com", Type: "ROBOT"}, false},
+		{"invalid role", Participant{Name: "A", Email: "a@example.com", Role: "SPEAKER"}, false},
+		{"invalid status", Participant{Name: "A", Email: "a@example.com", Status: "MAYBE"}, false},
+		{"delegated without delegate", Participant{Name: "A", Email: "a@example.com", Status: DelegatedParticipation}, false},
+		{"invalid delegate", Participant{Name: "A", Email: "a@example.com", DelegatedTo: []string{"nobody"}}, false},
+		{"invalid sent by", Participant{Name: "A", Email: "a@example.com", SentBy: "nobody"}, false},
+		{"directory", Participant{Name: "A", Email: "a@example.com", Directory: "https://example.com/people/a"}, true},
+		{"directory with quote", Participant{Name: "A", Email: "a@example.com", Directory: `https://example.com/"a"`}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.participant.valid(); got != tt.expected {
+				t.Errorf("Participant.valid() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEventAttendeeStatus(t *testing.T) {
+	event := mockEvent()
+	event.Attendees = []Participant{{Name: "A", Email: "a@example.com", Status: CompletedParticipation}}
+	if event.Valid() {
+		t.Errorf("Expected event attendee with VTODO only status to be invalid")
+	}
+
+	event.Attendees[0].Status = AcceptedParticipation
+	if !event.Valid() {
+		t.Errorf("Expected event attendee with ACCEPTED status to be valid")
+	}
+}
+
+func TestGenerateReminderRecipient(t *testing.T) {
+	reminder := Reminder{
+		Action:      EmailReminderAction,
+		Description: "Email Reminder",
+		Trigger:     -time.Hour,
+		Attendees:   []Participant{{Name: "John", Email: "john@example.com", Role: OptionalRole}},
+	}
+	var builder strings.Builder
+	err := reminder.generate(&builder)
+	if err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	if !strings.Contains(builder.String(), "ATTENDEE;CN=John:mailto:john@example.com\r\n") {
+		t.Errorf("Generated reminder missing recipient: %s", builder.String())
+	}
+}
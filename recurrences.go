@@ -16,75 +16,142 @@ type Recurrences struct {
 	Day time.Weekday
 
 	// REQUIRED: Start and end time for each occurrence
+	//
+	// Only the time of day is used, as a wall-clock time in the time zone of the event.
+	// It must be in UTC, e.g. time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), or in the location of that time zone.
 	StartTime time.Time
 
 	// REQUIRED: End time for each occurrence
+	//
+	// Not required if Duration is set. A wall-clock time like StartTime
 	EndTime time.Time
 
+	// OPTIONAL: Length of each occurrence, as an alternative to EndTime
+	//
+	// Written as DURATION instead of DTEND
+	Duration *Duration
+
 	// OPTIONAL: List of exception dates for the recurrence
 	Exceptions []time.Time
+
+	// OPTIONAL: Last date/time an instance of a To-Do may start
+	//
+	// Events recur until their EndDate. A To-Do without Until recurs forever
+	Until time.Time
 }
 
 func (r *Recurrences) Generate(startDate, endDate time.Time, timeZone TimeZone) (string, error) {
-	if !r.Valid() {
-		return "", ErrInvalidRecurrence
+	return r.generate(startDate, endDate, timeZone, generateOptions{})
+}
+
+func (r *Recurrences) generate(startDate, endDate time.Time, timeZone TimeZone, opts generateOptions) (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
 	}
 
+	// The dates are instants, the times of day are wall-clock times in the time zone
+	startDate = timeZone.localTime(startDate)
+	endDate = timeZone.localTime(endDate)
+
 	startTime, err := findStartDate(startDate, r.Day, r.StartTime)
 	if err != nil {
 		return "", err
 	}
-	endTime, err := findEndDate(endDate, r.Day, r.EndTime)
+	endTime, err := findEndDate(endDate, r.Day, r.endTime())
 	if err != nil {
 		return "", err
 	}
 	if endTime.Before(startTime) {
-		return "", ErrEndTimeBeforeStartTime(r.EndTime.String(), r.StartTime.String())
+		return "", ErrEndTimeBeforeStartTime(r.endTime().String(), r.StartTime.String())
 	}
 
+	tzid := paramValue(opts.tzid(timeZone))
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("DTSTART;TZID=%s:%s", timeZone.ID(), timeToICal(startTime)))
+	builder.WriteString(fmt.Sprintf("DTSTART;TZID=%s:%s", tzid, timeToICal(startTime)))
 	builder.WriteString(lineBreak)
-	builder.WriteString(fmt.Sprintf("DTEND;TZID=%s:%s", timeZone.ID(), timeToICal(startTime.Add(r.EndTime.Sub(r.StartTime)))))
+	if r.Duration != nil {
+		builder.WriteString("DURATION:" + r.Duration.String())
+	} else {
+		builder.WriteString(fmt.Sprintf("DTEND;TZID=%s:%s", tzid, timeToICal(startTime.Add(r.EndTime.Sub(r.StartTime)))))
+	}
 	builder.WriteString(lineBreak)
 	builder.WriteString(generateRRULE(r.Frequency, r.Day, endTime))
 	if len(r.Exceptions) > 0 {
 		for _, ex := range r.Exceptions {
 			builder.WriteString(lineBreak)
-			builder.WriteString(fmt.Sprintf("EXDATE;TZID=%s:%s", timeZone.ID(), timeToICal(ex)))
+			builder.WriteString(fmt.Sprintf("EXDATE;TZID=%s:%s", tzid, timeToICal(exceptionStart(timeZone.localDate(ex), startTime))))
 		}
 	}
 
 	return builder.String(), nil
 }
 
+// The start of the occurrence on the date of the exception, as an EXDATE must match the DTSTART
+func exceptionStart(date, start time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(),
+		start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+}
+
 func generateRRULE(f Frequency, d time.Weekday, endTime time.Time) string {
 	return fmt.Sprintf("RRULE:FREQ=%s;BYDAY=%s;UNTIL=%s;"+lineBreak, f, weekdayToICal(d), fmt.Sprintf("%sZ", timeToICal(endTime.UTC())))
 }
 
 func (r *Recurrences) Valid() bool {
-	if !r.Frequency.Valid() {
-		return false
-	}
-	if !validWeekday(r.Day) {
-		return false
+	return len(r.validationErrors()) == 0
+}
+
+// Validate explains why the recurrence is not valid, with a ValidationError for every broken rule.
+func (r *Recurrences) Validate() error {
+	return r.validationErrors().err()
+}
+
+func (r *Recurrences) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidRecurrence}
+	v.check(r.Frequency.Valid(), "Frequency", "is not a known frequency", r.Frequency)
+	v.checkErr(validWeekday(r.Day), ErrInvalidDayOfWeek, "Day", "is not a day of the week", r.Day)
+	if r.Duration != nil {
+		v.check(r.EndTime.IsZero(), "Duration", "can not be used with EndTime", nil)
+		v.check(r.Duration.positive(), "Duration", "must be positive", *r.Duration)
+	} else if r.EndTime.IsZero() {
+		v.check(false, "EndTime", "is required without Duration", nil)
+	} else {
+		v.check(r.EndTime.After(r.StartTime), "EndTime", "must be after StartTime", r.EndTime)
 	}
-	if r.EndTime.Before(r.StartTime) || r.EndTime.Equal(r.StartTime) {
-		return false
+	return v.errs
+}
+
+// Records a ValidationError at path when StartTime or EndTime is in a location other than UTC or the time zone,
+// as their times of day are written as wall-clock times in the time zone
+func (v *validation) checkClocks(r *Recurrences, tz TimeZone, path string) {
+	for _, clock := range []struct {
+		name string
+		time time.Time
+	}{{"StartTime", r.StartTime}, {"EndTime", r.EndTime}} {
+		if clock.time.IsZero() {
+			continue
+		}
+		loc := clock.time.Location().String()
+		v.check(loc == "UTC" || loc == tz.ID(), path+"."+clock.name, "must be in UTC or the time zone, it is a wall-clock time", loc)
 	}
+}
 
-	return true
+// End time of each occurrence, computed from Duration when it is set
+func (r *Recurrences) endTime() time.Time {
+	if r.Duration != nil {
+		return r.Duration.AddTo(r.StartTime)
+	}
+	return r.EndTime
 }
 
 func (r *Recurrences) uid() string {
 	return fmt.Sprintf("%s-%s-%s@iCal.go", r.Frequency,
-		r.StartTime.UTC().Format("15_04"), r.EndTime.UTC().Format("15_04"))
+		r.StartTime.UTC().Format("15_04"), r.endTime().UTC().Format("15_04"))
 }
 func (r *Recurrences) ConflictsWith(other Recurrences) (bool, time.Time) {
 	if r.Day != other.Day {
 		return false, time.Time{}
 	}
-	if stripDay(r.StartTime).Before(stripDay(other.EndTime)) && stripDay(other.StartTime).Before(stripDay(r.EndTime)) {
+	if stripDay(r.StartTime).Before(stripDay(other.endTime())) && stripDay(other.StartTime).Before(stripDay(r.endTime())) {
 		return true, time.Date(0, 1, 1, r.StartTime.Hour(), r.StartTime.Minute(), r.StartTime.Second(), 0, time.UTC)
 	}
 
@@ -112,19 +179,33 @@ func (r *Recurrences) Occurrences(startDate time.Time, endDate time.Time) []time
 			current.Location(),
 		)
 		occurrences = append(occurrences, occurrence)
+		current = r.advance(current)
+	}
+	return occurrences
+}
 
-		switch r.Frequency {
-		case DailyFrequency:
-			current = current.AddDate(0, 0, 1)
-		case WeeklyFrequency:
-			current = current.AddDate(0, 0, 7)
-		case MonthlyFrequency:
-			current = current.AddDate(0, 1, 0)
-		case YearlyFrequency:
-			current = current.AddDate(1, 0, 0)
+// The date one interval of the frequency after t
+//
+// Like an RRULE, monthly and yearly instances keep the day of the month of t,
+// and skip the months without it, e.g. the 31st recurs on Mar 31 after Jan 31.
+func (r *Recurrences) advance(t time.Time) time.Time {
+	var months int
+	switch r.Frequency {
+	case DailyFrequency:
+		return t.AddDate(0, 0, 1)
+	case WeeklyFrequency:
+		return t.AddDate(0, 0, 7)
+	case MonthlyFrequency:
+		months = 1
+	default:
+		months = 12
+	}
+	for n := months; ; n += months {
+		next := time.Date(t.Year(), t.Month()+time.Month(n), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+		if next.Day() == t.Day() {
+			return next
 		}
 	}
-	return occurrences
 }
 
 func weekdayToICal(d time.Weekday) string {
@@ -77,6 +77,30 @@ func TestGenerateRecurrencesWithException(t *testing.T) {
 	}
 }
 
+func TestGenerateRecurrenceDuration(t *testing.T) {
+	rec := Recurrences{
+		Frequency: WeeklyFrequency,
+		Day:       time.Monday,
+		StartTime: time.Date(0, 0, 0, 9, 0, 0, 0, time.UTC),
+		Duration:  &Duration{Time: 45 * time.Minute},
+	}
+	if !rec.Valid() {
+		t.Fatalf("Expected recurrence with DURATION to be valid")
+	}
+	s, err := rec.Generate(time.Now(), time.Now().Add(21*24*time.Hour), TimeZone(timezones.US_Eastern))
+	if err != nil {
+		t.Fatalf("Recurrences.Generate() returned error: %v", err)
+	}
+	if !strings.Contains(s, "DURATION:PT45M") || strings.Contains(s, "DTEND") {
+		t.Errorf("Expected DURATION instead of DTEND in output: %s", s)
+	}
+
+	rec.EndTime = time.Date(0, 0, 0, 10, 0, 0, 0, time.UTC)
+	if rec.Valid() {
+		t.Errorf("Expected recurrence with both EndTime and DURATION to be invalid")
+	}
+}
+
 func TestConflictsWithRecurrences(t *testing.T) {
 	r := mockRecurrence()
 	other := Recurrences{
@@ -227,6 +251,46 @@ func TestRecurrenceUID(t *testing.T) {
 	}
 }
 
+func TestRecurrenceAdvance(t *testing.T) {
+	var tests = []struct {
+		frequency Frequency
+		start     time.Time
+		expected  []time.Time
+	}{
+		{MonthlyFrequency, time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2025, time.March, 31, 9, 0, 0, 0, time.UTC),
+			time.Date(2025, time.May, 31, 9, 0, 0, 0, time.UTC),
+			time.Date(2025, time.July, 31, 9, 0, 0, 0, time.UTC),
+		}},
+		{MonthlyFrequency, time.Date(2025, time.January, 29, 9, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2025, time.March, 29, 9, 0, 0, 0, time.UTC),
+			time.Date(2025, time.April, 29, 9, 0, 0, 0, time.UTC),
+		}},
+		{MonthlyFrequency, time.Date(2025, time.November, 30, 9, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2025, time.December, 30, 9, 0, 0, 0, time.UTC),
+			time.Date(2026, time.January, 30, 9, 0, 0, 0, time.UTC),
+			time.Date(2026, time.March, 30, 9, 0, 0, 0, time.UTC),
+		}},
+		{YearlyFrequency, time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2028, time.February, 29, 9, 0, 0, 0, time.UTC),
+		}},
+		{WeeklyFrequency, time.Date(2025, time.January, 27, 9, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2025, time.February, 3, 9, 0, 0, 0, time.UTC),
+		}},
+	}
+	for _, tt := range tests {
+		rec := Recurrences{Frequency: tt.frequency}
+		current := tt.start
+		for _, expected := range tt.expected {
+			current = rec.advance(current)
+			if !current.Equal(expected) {
+				t.Errorf("advance() of %s series from %v = %v, want %v", tt.frequency, tt.start, current, expected)
+				break
+			}
+		}
+	}
+}
+
 func mockRecurrence() Recurrences {
 	return Recurrences{
 		Frequency: WeeklyFrequency,
@@ -0,0 +1,210 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// iCalendar RELATED-TO property
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-8-4-5-related-to.html
+type Relation struct {
+	// REQUIRED: UID of the related component
+	UID string
+
+	// OPTIONAL: How the related component relates to this one
+	//
+	// Possible Values: ParentRelation, ChildRelation, SiblingRelation, DependsOnRelation
+	//
+	// Defaults to ParentRelation
+	Type RelationType
+
+	// OPTIONAL: Start of the occurrence of a recurring component the relation refers to
+	//
+	// RFC 5545 only relates whole components, so it is written as the X-RECURRENCE-ID parameter, in UTC
+	Occurrence time.Time
+}
+
+// The type of hierarchical relationship between two components
+type RelationType string
+
+const (
+	// The related component is the parent of this one
+	ParentRelation RelationType = "PARENT"
+
+	// The related component is a child of this one
+	ChildRelation RelationType = "CHILD"
+
+	// The related component shares a parent with this one
+	SiblingRelation RelationType = "SIBLING"
+
+	// The related component must be completed before this one, RFC 9253
+	DependsOnRelation RelationType = "DEPENDS-ON"
+)
+
+// Generate the iCalendar RELATED-TO property
+func (r *Relation) generate(builder *strings.Builder) error {
+	if !r.valid() {
+		return ErrInvalidRelation
+	}
+	line := "RELATED-TO"
+	if r.Type != "" {
+		line += ";RELTYPE=" + string(r.Type)
+	}
+	if !r.Occurrence.IsZero() {
+		line += ";X-RECURRENCE-ID=" + timeToICal(r.Occurrence.UTC()) + "Z"
+	}
+	builder.WriteString(foldLine(line+":"+r.UID) + lineBreak)
+	return nil
+}
+
+func (r *Relation) valid() bool {
+	return len(r.validationErrors()) == 0
+}
+
+func (r *Relation) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidRelation}
+	v.check(r.UID != "", "UID", "is required", nil)
+	v.check(r.Type == "" || r.Type.valid(), "Type", "is not a known relation type", r.Type)
+	return v.errs
+}
+
+func (t *RelationType) valid() bool {
+	switch *t {
+	case ParentRelation, ChildRelation, SiblingRelation, DependsOnRelation:
+		return true
+	default:
+		return false
+	}
+}
+
+// The relationship type, applying the RFC 5545 default
+func (r *Relation) relType() RelationType {
+	if r.Type == "" {
+		return ParentRelation
+	}
+	return r.Type
+}
+
+// A component and its children, as resolved by Calendar.RelationTree
+type RelationNode struct {
+	// UID of the component
+	UID string
+
+	// The *Event, *Todo or *Journal
+	//
+	// For a parsed series with overrides of single occurrences (RECURRENCE-ID), the series,
+	// its overrides share the node
+	Component Component
+
+	// Components whose parent is this component
+	Children []*RelationNode
+}
+
+// RelationTree resolves the RELATED-TO properties of every component into
+// parent/child hierarchies and returns the root nodes.
+//
+// PARENT and CHILD relations may be declared on either side, or on both.
+// SIBLING and DEPENDS-ON relations are checked for dangling references but add no edges.
+//
+// Components are nodes by UID. Overrides of single occurrences of a parsed series share the node of
+// the series, with their relations added to it. Components without a UID are nodes of their own,
+// and can not be the target of a relation.
+//
+// Returns ErrDanglingRelation when a relation points to a UID that is not in
+// the calendar, ErrRelationCycle when a component is its own ancestor and
+// ErrDuplicateUID when components that are not occurrences of one another share a UID.
+func (c *Calendar) RelationTree() ([]*RelationNode, error) {
+	components := c.components()
+	nodes := make(map[string]*RelationNode, len(components))
+	// Every component with the UID, the series and its overrides
+	members := make(map[string][]Component, len(components))
+	var order []string
+	for i, component := range components {
+		uid := component.uid()
+		// The generated UID of a component without one is not unique, the key is its position instead
+		key := uid
+		if !hasUID(component) {
+			key = fmt.Sprintf("\x00%d", i)
+		}
+		override := recurrenceID(component) != ""
+		node, exists := nodes[key]
+		switch {
+		case !exists:
+			order = append(order, key)
+			nodes[key] = &RelationNode{UID: uid, Component: component}
+		case !override && recurrenceID(node.Component) == "":
+			return nil, ErrDuplicateUIDFor(uid)
+		case !override:
+			// The series, read after one of its overrides
+			node.Component = component
+		}
+		members[key] = append(members[key], component)
+	}
+
+	parents := make(map[string][]string)
+	addEdge := func(parent, child string) {
+		for _, p := range parents[child] {
+			if p == parent {
+				return
+			}
+		}
+		parents[child] = append(parents[child], parent)
+		nodes[parent].Children = append(nodes[parent].Children, nodes[child])
+	}
+
+	for _, key := range order {
+		for _, member := range members[key] {
+			for _, rel := range member.relations() {
+				if _, exists := nodes[rel.UID]; !exists {
+					return nil, ErrDanglingRelationFor(nodes[key].UID, rel.UID)
+				}
+				switch rel.relType() {
+				case ParentRelation:
+					addEdge(rel.UID, key)
+				case ChildRelation:
+					addEdge(key, rel.UID)
+				}
+			}
+		}
+	}
+
+	// Depth first search for a path back to a component already on the stack
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[*RelationNode]int, len(nodes))
+	var visit func(node *RelationNode) error
+	visit = func(node *RelationNode) error {
+		switch state[node] {
+		case visiting:
+			return ErrRelationCycleAt(node.UID)
+		case done:
+			return nil
+		}
+		state[node] = visiting
+		for _, child := range node.Children {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		state[node] = done
+		return nil
+	}
+
+	var roots []*RelationNode
+	for _, key := range order {
+		if len(parents[key]) == 0 {
+			roots = append(roots, nodes[key])
+		}
+	}
+	for _, key := range order {
+		if err := visit(nodes[key]); err != nil {
+			return nil, err
+		}
+	}
+	return roots, nil
+}
@@ -0,0 +1,217 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
+)
+
+func TestGenerateRelation(t *testing.T) {
+	var tests = []struct {
+		relation Relation
+		expected string
+	}{
+		{Relation{UID: "parent@example.com"}, "RELATED-TO:parent@example.com\r\n"},
+		{Relation{UID: "child@example.com", Type: ChildRelation}, "RELATED-TO;RELTYPE=CHILD:child@example.com\r\n"},
+		{Relation{UID: "sibling@example.com", Type: SiblingRelation}, "RELATED-TO;RELTYPE=SIBLING:sibling@example.com\r\n"},
+		{Relation{UID: "design@example.com", Type: DependsOnRelation}, "RELATED-TO;RELTYPE=DEPENDS-ON:design@example.com\r\n"},
+		{Relation{UID: "a@b", Occurrence: time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC)}, "RELATED-TO;X-RECURRENCE-ID=20250113T090000Z:a@b\r\n"},
+	}
+	for _, tt := range tests {
+		var builder strings.Builder
+		err := tt.relation.generate(&builder)
+		if err != nil {
+			t.Fatalf("generate() returned error: %v", err)
+		}
+		if builder.String() != tt.expected {
+			t.Errorf("generate() = %q, want %q", builder.String(), tt.expected)
+		}
+	}
+
+	invalid := Relation{UID: "x@example.com", Type: "COUSIN"}
+	var builder strings.Builder
+	if err := invalid.generate(&builder); err != ErrInvalidRelation {
+		t.Errorf("generate() = %v, want %v", err, ErrInvalidRelation)
+	}
+}
+
+func TestRelationTree(t *testing.T) {
+	cal := Create("Projects", "Project tracking")
+	cal.Todos = []Todo{
+		{UID: "project", Summary: "Launch", Relations: []Relation{{UID: "task-2", Type: ChildRelation}}},
+		{UID: "task-1", Summary: "Design", Relations: []Relation{{UID: "project", Type: ParentRelation}}},
+		{UID: "task-2", Summary: "Build", Relations: []Relation{{UID: "project"}, {UID: "task-1", Type: SiblingRelation}}},
+	}
+	cal.Events = []Event{{
+		UID:       "follow-up",
+		Title:     "Follow-up",
+		StartDate: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+		TimeZone:  TimeZone(timezones.UTC),
+		Relations: []Relation{{UID: "project"}},
+	}}
+
+	roots, err := cal.RelationTree()
+	if err != nil {
+		t.Fatalf("RelationTree() returned error: %v", err)
+	}
+	if len(roots) != 1 || roots[0].UID != "project" {
+		t.Fatalf("RelationTree() roots = %v, want [project]", roots)
+	}
+
+	var children []string
+	for _, child := range roots[0].Children {
+		children = append(children, child.UID)
+	}
+	if strings.Join(children, ",") != "follow-up,task-2,task-1" {
+		t.Errorf("project children = %v, want [follow-up task-2 task-1]", children)
+	}
+	if _, ok := roots[0].Component.(*Todo); !ok {
+		t.Errorf("Expected root component to be a *Todo, got %T", roots[0].Component)
+	}
+}
+
+func TestRelationTreeErrors(t *testing.T) {
+	cycle := Create("Cycle", "Components relating in a loop")
+	cycle.Todos = []Todo{
+		{UID: "a", Summary: "A", Relations: []Relation{{UID: "b"}}},
+		{UID: "b", Summary: "B", Relations: []Relation{{UID: "c"}}},
+		{UID: "c", Summary: "C", Relations: []Relation{{UID: "a"}}},
+	}
+	if _, err := cycle.RelationTree(); !errors.Is(err, ErrRelationCycle) {
+		t.Errorf("RelationTree() = %v, want %v", err, ErrRelationCycle)
+	}
+
+	dangling := Create("Dangling", "Component relating to nothing")
+	dangling.Journals = []Journal{
+		{UID: "minutes", Summary: "Minutes", Description: "Notes", Relations: []Relation{{UID: "missing"}}},
+	}
+	_, err := dangling.RelationTree()
+	if !errors.Is(err, ErrDanglingRelation) {
+		t.Errorf("RelationTree() = %v, want %v", err, ErrDanglingRelation)
+	}
+	if err != nil && !strings.Contains(err.Error(), "'missing'") {
+		t.Errorf("Expected error to name the missing UID, got %v", err)
+	}
+
+	duplicate := Create("Duplicate", "Components sharing a UID")
+	duplicate.Todos = []Todo{
+		{UID: "a", Summary: "A", Relations: []Relation{{UID: "b"}}},
+		{UID: "a", Summary: "Other A"},
+		{UID: "b", Summary: "B"},
+	}
+	if _, err := duplicate.RelationTree(); !errors.Is(err, ErrDuplicateUID) {
+		t.Errorf("RelationTree() = %v, want %v", err, ErrDuplicateUID)
+	}
+}
+
+func TestRelationTreeWithoutUIDs(t *testing.T) {
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	cal := Create("Standups", "Components without a UID")
+	cal.Events = []Event{
+		{Title: "Standup", StartDate: start, EndDate: start.Add(15 * time.Minute), TimeZone: "UTC"},
+		{Title: "Standup", StartDate: start, EndDate: start.Add(15 * time.Minute), TimeZone: "UTC", Relations: []Relation{{UID: "sprint"}}},
+	}
+	cal.Todos = []Todo{
+		{UID: "sprint", Summary: "Sprint"},
+		{Summary: "Notes"},
+		{Summary: "Notes"},
+	}
+
+	roots, err := cal.RelationTree()
+	if err != nil {
+		t.Fatalf("RelationTree() returned error: %v", err)
+	}
+	if len(roots) != 4 {
+		t.Fatalf("RelationTree() = %d roots, want 4", len(roots))
+	}
+	for _, root := range roots {
+		if root.UID == "sprint" && (len(root.Children) != 1 || root.Children[0].Component != &cal.Events[1]) {
+			t.Errorf("RelationTree() sprint children = %v, want the second standup", root.Children)
+		}
+	}
+}
+
+func TestRelationTreeOverrides(t *testing.T) {
+	cal, err := Parse(strings.NewReader(calendarData(
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VTODO",
+		"UID:project",
+		"SUMMARY:Launch",
+		"END:VTODO",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"RECURRENCE-ID;TZID=Europe/Paris:20250113T090000",
+		"SUMMARY:Standup moved",
+		"DTSTART;TZID=Europe/Paris:20250113T110000",
+		"DTEND;TZID=Europe/Paris:20250113T111500",
+		"RELATED-TO;RELTYPE=PARENT:project",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:standup",
+		"SUMMARY:Standup",
+		"DTSTART;TZID=Europe/Paris:20250106T090000",
+		"DTEND;TZID=Europe/Paris:20250106T091500",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=4",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+
+	roots, err := cal.RelationTree()
+	if err != nil {
+		t.Fatalf("RelationTree() returned error: %v", err)
+	}
+	if len(roots) != 1 || len(roots[0].Children) != 1 {
+		t.Fatalf("RelationTree() roots = %v, want the project with the standup", roots)
+	}
+	standup := roots[0].Children[0]
+	if event, ok := standup.Component.(*Event); !ok || event.Title != "Standup" {
+		t.Errorf("Component = %+v, want the series", standup.Component)
+	}
+}
+
+func TestRelationsInOutput(t *testing.T) {
+	todo := Todo{UID: "task-1", Summary: "Design", Relations: []Relation{{UID: "project", Type: ParentRelation}}}
+	var builder strings.Builder
+	if err := todo.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	if !strings.Contains(builder.String(), "UID:task-1\r\n") {
+		t.Errorf("Generated todo missing UID: %s", builder.String())
+	}
+	if !strings.Contains(builder.String(), "RELATED-TO;RELTYPE=PARENT:project\r\n") {
+		t.Errorf("Generated todo missing RELATED-TO: %s", builder.String())
+	}
+
+	todo.Relations = append(todo.Relations, Relation{})
+	if todo.valid() {
+		t.Errorf("Expected todo with empty relation UID to be invalid")
+	}
+}
+
+func TestEventRecurrenceUID(t *testing.T) {
+	event := mockEvent()
+	event.UID = "weekly@example.com"
+	event.Recurrences = append(event.Recurrences, Recurrences{
+		Frequency: WeeklyFrequency,
+		Day:       time.Monday,
+		StartTime: time.Date(0, 0, 0, 11, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(0, 0, 0, 12, 0, 0, 0, time.UTC),
+	})
+
+	output, err := event.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if !strings.Contains(output, "UID:weekly@example.com\r\n") || !strings.Contains(output, "UID:weekly@example.com-1\r\n") {
+		t.Errorf("Expected a distinct UID for each recurrence: %s", output)
+	}
+}
@@ -2,6 +2,7 @@ package ical
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
@@ -21,18 +22,79 @@ type Reminder struct {
 	// **EmailReminderAction** requires SUMMARY & ATTENDEES properties
 	Action ReminderAction
 
-	// REQUIRED: Time before the event when the reminder should trigger
+	// OPTIONAL: Subject of the email
+	//
+	// **Only for EMAIL action**, where RFC 5545 requires it
+	Summary string
+
+	// OPTIONAL: Offset from the start of the event when the reminder should trigger
+	//
+	// Negative values trigger before, zero triggers at the start time.
+	// Set TriggerRelation to EndTriggerRelation to trigger relative to the end instead.
 	Trigger time.Duration
 
+	// OPTIONAL: Whether Trigger is relative to the start or the end of the event
+	//
+	// Possible Values: StartTriggerRelation, EndTriggerRelation
+	//
+	// Defaults to StartTriggerRelation
+	TriggerRelation TriggerRelation
+
+	// OPTIONAL: Absolute date/time when the reminder should trigger, instead of Trigger
+	//
+	// Written in UTC as TRIGGER;VALUE=DATE-TIME
+	TriggerAt *time.Time
+
 	// OPTIONAL: Number of times the reminder should repeat
 	Repeat *int
 
+	// OPTIONAL: Time between repeats of the reminder
+	//
+	// Only used with Repeat, defaults to 15 minutes
+	RepeatInterval time.Duration
+
 	// OPTIONAL: List of attendees to notify
 	//
 	// **Only for EMAIL action**
 	Attendees []Participant
+
+	// OPTIONAL: Sound to play or files to send
+	//
+	// **AUDIO action allows a single attachment, EMAIL allows any number, DISPLAY allows none**
+	Attachments []Attachment
+
+	// OPTIONAL: Persistent, globally unique identifier of the reminder
+	//
+	// Required to snooze the reminder
+	//
+	// https://www.rfc-editor.org/rfc/rfc9074#section-4
+	UID string
+
+	// OPTIONAL: When the user last acknowledged (dismissed) the reminder
+	//
+	// Written in UTC as ACKNOWLEDGED
+	//
+	// https://www.rfc-editor.org/rfc/rfc9074#section-6
+	Acknowledged *time.Time
+
+	// OPTIONAL: UID of the reminder this reminder snoozes
+	//
+	// Written as RELATED-TO;RELTYPE=SNOOZE
+	//
+	// https://www.rfc-editor.org/rfc/rfc9074#section-7
+	Snoozes string
+
+	// OPTIONAL: Trigger the reminder on arrival or departure instead of at a time
+	//
+	// Possible Values: ArriveProximity, DepartProximity, ConnectProximity, DisconnectProximity
+	//
+	// https://www.rfc-editor.org/rfc/rfc9074#section-8
+	Proximity Proximity
 }
 
+// The DURATION written for a repeating reminder without a RepeatInterval
+const defaultRepeatInterval = 15 * time.Minute
+
 // The Action to be taken when the reminder is triggered
 type ReminderAction string
 
@@ -45,15 +107,52 @@ const (
 	AudioReminderAction ReminderAction = "AUDIO"
 )
 
+// The location event that triggers a reminder (RFC 9074 PROXIMITY)
+type Proximity string
+
+const (
+	// Trigger when arriving at the location of the event
+	ArriveProximity Proximity = "ARRIVE"
+
+	// Trigger when departing from the location of the event
+	DepartProximity Proximity = "DEPART"
+
+	// Trigger when connecting to a device, e.g. a car's bluetooth
+	ConnectProximity Proximity = "CONNECT"
+
+	// Trigger when disconnecting from a device
+	DisconnectProximity Proximity = "DISCONNECT"
+)
+
+// What a relative reminder Trigger is measured from
+type TriggerRelation string
+
+const (
+	// Trigger relative to the start of the event, or of the To-Do
+	StartTriggerRelation TriggerRelation = "START"
+
+	// Trigger relative to the end of the event, or the due date of the To-Do
+	EndTriggerRelation TriggerRelation = "END"
+)
+
 // Pretty print a user friendly representation of the reminder
 func (r *Reminder) String() string {
 	builder := strings.Builder{}
 	builder.WriteString("Reminder:\n")
 	builder.WriteString("  Description: " + r.Description + "\n")
 	builder.WriteString("  Action: " + string(r.Action) + "\n")
-	builder.WriteString("  Trigger: " + r.Trigger.String() + "\n")
+	if r.TriggerAt != nil {
+		builder.WriteString("  Trigger: at " + r.TriggerAt.String() + "\n")
+	} else if r.TriggerRelation == EndTriggerRelation {
+		builder.WriteString("  Trigger: " + r.Trigger.String() + " from end\n")
+	} else {
+		builder.WriteString("  Trigger: " + r.Trigger.String() + "\n")
+	}
 	if r.Repeat != nil {
-		builder.WriteString("  Repeats: " + fmt.Sprintf("%d", *r.Repeat) + " times\n")
+		builder.WriteString("  Repeats: " + fmt.Sprintf("%d", *r.Repeat) + " times, every " + r.RepeatInterval.String() + "\n")
+	}
+	if r.Acknowledged != nil {
+		builder.WriteString("  Acknowledged: " + r.Acknowledged.String() + "\n")
 	}
 
 	if r.Action == EmailReminderAction && len(r.Attendees) > 0 {
@@ -67,21 +166,44 @@ func (r *Reminder) String() string {
 
 // Generate iCalendar VALARM component
 func (r *Reminder) generate(builder *strings.Builder) error {
-	if !r.valid() {
-		return ErrInvalidReminder
+	if err := r.Validate(); err != nil {
+		return err
 	}
 	builder.WriteString("BEGIN:VALARM\r\n")
+	if r.UID != "" {
+		builder.WriteString(foldLine("UID:"+r.UID) + "\r\n")
+	}
 	builder.WriteString("ACTION:" + string(r.Action) + "\r\n")
 	builder.WriteString("DESCRIPTION:" + cleanDescription(r.Description) + "\r\n")
-	builder.WriteString("TRIGGER:" + formatDurationAsTrigger(r.Trigger) + "\r\n")
-	if r.Repeat != nil {
+	if r.Summary != "" {
+		builder.WriteString("SUMMARY:" + cleanDescription(r.Summary) + "\r\n")
+	}
+	builder.WriteString(r.formatTrigger() + "\r\n")
+	if r.Repeat != nil && *r.Repeat > 0 {
 		builder.WriteString("REPEAT:" + fmt.Sprintf("%d", *r.Repeat) + "\r\n")
-		// Assuming a fixed DURATION of 15 minutes for each repeat for simplicity
-		builder.WriteString("DURATION:PT15M\r\n")
+		builder.WriteString("DURATION:" + formatDurationAsTrigger(r.repeatInterval()) + "\r\n")
+	}
+	if r.Acknowledged != nil {
+		builder.WriteString("ACKNOWLEDGED:" + timeToICal(r.Acknowledged.UTC()) + "Z\r\n")
+	}
+	if r.Snoozes != "" {
+		builder.WriteString(foldLine("RELATED-TO;RELTYPE=SNOOZE:"+r.Snoozes) + "\r\n")
+	}
+	if r.Proximity != "" {
+		builder.WriteString("PROXIMITY:" + string(r.Proximity) + "\r\n")
 	}
 	if r.Action == EmailReminderAction && len(r.Attendees) > 0 {
 		for _, attendee := range r.Attendees {
-			builder.WriteString("ATTENDEE;CN=" + attendee.Name + ":MAILTO:" + attendee.Email + "\r\n")
+			err := attendee.generateRecipient(builder)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, attachment := range r.Attachments {
+		err := attachment.generate(builder)
+		if err != nil {
+			return err
 		}
 	}
 	builder.WriteString("END:VALARM\r\n")
@@ -93,74 +215,131 @@ func (r *Reminder) addTrigger(duration time.Duration) {
 }
 
 func (r *Reminder) valid() bool {
-	if r.Description == "" {
-		return false
+	return len(r.validationErrors()) == 0
+}
+
+// Validate explains why the reminder is not valid, with a ValidationError for every broken rule.
+func (r *Reminder) Validate() error {
+	return r.validationErrors().err()
+}
+
+func (r *Reminder) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidReminder}
+	v.check(r.Description != "", "Description", "is required", nil)
+	v.check(r.Action == DisplayReminderAction || r.Action == EmailReminderAction || r.Action == AudioReminderAction,
+		"Action", "is not a known action", r.Action)
+
+	if r.Action == EmailReminderAction {
+		v.check(len(r.Attendees) > 0, "Attendees", "are required for EMAIL reminders", nil)
+	} else {
+		v.check(len(r.Attendees) == 0, "Attendees", "can only be used on EMAIL reminders", nil)
 	}
-	if r.Action != DisplayReminderAction && r.Action != EmailReminderAction && r.Action != AudioReminderAction {
-		return false
+	for i := range r.Attendees {
+		v.nest(indexPath("Attendees", i), r.Attendees[i].validationErrors())
 	}
+	v.check(r.Summary == "" || r.Action == EmailReminderAction, "Summary", "can only be used on EMAIL reminders", r.Summary)
 
-	if r.Action == EmailReminderAction && len(r.Attendees) == 0 {
-		return false
-	}
-	if r.Action != EmailReminderAction && len(r.Attendees) > 0 {
-		return false
+	v.check(r.Action != DisplayReminderAction || len(r.Attachments) == 0, "Attachments", "can not be used on DISPLAY reminders", nil)
+	v.check(r.Action != AudioReminderAction || len(r.Attachments) <= 1, "Attachments", "can only hold one sound", len(r.Attachments))
+	for i := range r.Attachments {
+		v.nest(indexPath("Attachments", i), r.Attachments[i].validationErrors())
 	}
 
-	if r.Repeat != nil && *r.Repeat < 0 {
-		return false
+	if r.Repeat != nil {
+		v.check(*r.Repeat >= 0, "Repeat", "can not be negative", *r.Repeat)
 	}
+	// REPEAT and DURATION must occur together, DURATION defaults when it is not set
+	repeats := r.Repeat != nil && *r.Repeat > 0
+	v.check(r.RepeatInterval >= 0, "RepeatInterval", "can not be negative", r.RepeatInterval)
+	v.check(repeats || r.RepeatInterval == 0, "RepeatInterval", "requires Repeat", r.RepeatInterval)
 
-	if r.Trigger == 0 {
-		return false
+	v.check(r.Proximity == "" || r.Proximity.valid(), "Proximity", "is not a known proximity", r.Proximity)
+	v.check(r.TriggerRelation == "" || r.TriggerRelation.valid(), "TriggerRelation", "is not a known trigger relation", r.TriggerRelation)
+	if r.TriggerAt != nil {
+		// An absolute trigger can not also be relative
+		v.check(!r.TriggerAt.IsZero(), "TriggerAt", "can not be the zero time", nil)
+		v.check(r.Trigger == 0, "Trigger", "can not be used with TriggerAt", r.Trigger)
+		v.check(r.TriggerRelation == "", "TriggerRelation", "can not be used with TriggerAt", r.TriggerRelation)
 	}
+	return v.errs
+}
 
-	return true
+// Acknowledge records that the user dismissed the reminder at the given time.
+func (r *Reminder) Acknowledge(at time.Time) {
+	acknowledged := at.UTC()
+	r.Acknowledged = &acknowledged
 }
 
-// Format time.Duration as iCal TRIGGER value
-func formatDurationAsTrigger(d time.Duration) string {
-	// iCal duration format is using ISO 8601 Durations
-	// P(n)Y(n)M(n)DT(n)H(n)M(n)S
-	isNegative := d < 0
-	if isNegative {
-		d = -d
+// Snooze acknowledges the reminder at the given time, and returns a new reminder
+// that triggers again at until.
+//
+// The new reminder is related to this one with RELATED-TO;RELTYPE=SNOOZE,
+// so the reminder must have a UID.
+func (r *Reminder) Snooze(at, until time.Time) (Reminder, error) {
+	if r.UID == "" || !until.After(at) {
+		return Reminder{}, ErrInvalidReminder
 	}
+	r.Acknowledge(at)
 
-	totalSeconds := int(d.Seconds())
-	seconds := totalSeconds % 60
-	minutes := (totalSeconds / 60) % 60
-	hours := (totalSeconds / (60 * 60))
-	days := (totalSeconds / (60 * 60)) / 24
+	trigger := until.UTC()
+	return Reminder{
+		UID:         fmt.Sprintf("%s-snooze-%d", r.UID, trigger.Unix()),
+		Description: r.Description,
+		Action:      r.Action,
+		Summary:     r.Summary,
+		Attendees:   slices.Clone(r.Attendees),
+		Attachments: slices.Clone(r.Attachments),
+		TriggerAt:   &trigger,
+		Snoozes:     r.UID,
+		Proximity:   r.Proximity,
+	}, nil
+}
 
-	if days > 0 {
-		hours = hours % 24
+func (p *Proximity) valid() bool {
+	switch *p {
+	case ArriveProximity, DepartProximity, ConnectProximity, DisconnectProximity:
+		return true
+	default:
+		return false
 	}
+}
 
-	var builder strings.Builder
-	if isNegative {
-		builder.WriteString("-")
+func (r *TriggerRelation) valid() bool {
+	switch *r {
+	case StartTriggerRelation, EndTriggerRelation:
+		return true
+	default:
+		return false
 	}
-	builder.WriteString("P")
+}
 
-	if days > 0 {
-		builder.WriteString(fmt.Sprintf("%dD", days))
-	}
+// Whether the reminder triggers relative to the end of the event, or the due date of the To-Do
+func (r *Reminder) relatedToEnd() bool {
+	return r.TriggerAt == nil && r.TriggerRelation == EndTriggerRelation
+}
 
-	hasTime := hours > 0 || minutes > 0 || seconds > 0
-	if hasTime {
-		builder.WriteString("T")
+// Time between repeats of the reminder, 15 minutes when RepeatInterval is not set
+func (r *Reminder) repeatInterval() time.Duration {
+	if r.RepeatInterval == 0 {
+		return defaultRepeatInterval
 	}
+	return r.RepeatInterval
+}
 
-	if hours > 0 {
-		builder.WriteString(fmt.Sprintf("%dH", hours))
-	}
-	if minutes > 0 {
-		builder.WriteString(fmt.Sprintf("%dM", minutes))
+// The TRIGGER property of the reminder
+//
+// https://icalendar.org/iCalendar-RFC-5545/3-8-6-3-trigger.html
+func (r *Reminder) formatTrigger() string {
+	if r.TriggerAt != nil {
+		return "TRIGGER;VALUE=DATE-TIME:" + timeToICal(r.TriggerAt.UTC()) + "Z"
 	}
-	if seconds > 0 || !hasTime {
-		builder.WriteString(fmt.Sprintf("%dS", seconds))
+	if r.relatedToEnd() {
+		return "TRIGGER;RELATED=END:" + formatDurationAsTrigger(r.Trigger)
 	}
+	return "TRIGGER:" + formatDurationAsTrigger(r.Trigger)
+}
 
-	return builder.String()
+// Format time.Duration as iCal TRIGGER value
+func formatDurationAsTrigger(d time.Duration) string {
+	return NewDuration(d).String()
 }
@@ -92,6 +92,175 @@ func TestAddDuration(t *testing.T) {
 	}
 }
 
+func TestReminderTriggers(t *testing.T) {
+	at := time.Date(2025, 3, 1, 13, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+	var tests = []struct {
+		name     string
+		reminder Reminder
+		expected string
+	}{
+		{"before start", Reminder{Trigger: -15 * time.Minute}, "TRIGGER:-PT15M"},
+		{"at start", Reminder{}, "TRIGGER:PT0S"},
+		{"before end", Reminder{Trigger: -10 * time.Minute, TriggerRelation: EndTriggerRelation}, "TRIGGER;RELATED=END:-PT10M"},
+		{"explicit start", Reminder{Trigger: time.Hour, TriggerRelation: StartTriggerRelation}, "TRIGGER:PT1H"},
+		{"absolute", Reminder{TriggerAt: &at}, "TRIGGER;VALUE=DATE-TIME:20250301T180000Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminder := tt.reminder
+			reminder.Action = DisplayReminderAction
+			reminder.Description = "Trigger"
+			if !reminder.valid() {
+				t.Fatalf("Expected reminder to be valid: %+v", reminder)
+			}
+
+			var builder strings.Builder
+			err := reminder.generate(&builder)
+			if err != nil {
+				t.Fatalf("generate() returned error: %v", err)
+			}
+			if !strings.Contains(builder.String(), tt.expected+"\r\n") {
+				t.Errorf("Expected %s in generated data: %s", tt.expected, builder.String())
+			}
+		})
+	}
+}
+
+func TestInvalidReminderTriggers(t *testing.T) {
+	at := time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC)
+	invalidReminders := []Reminder{
+		{Action: DisplayReminderAction, Description: "Desc", TriggerRelation: "MIDDLE"},
+		{Action: DisplayReminderAction, Description: "Desc", TriggerAt: &at, Trigger: -time.Minute},
+		{Action: DisplayReminderAction, Description: "Desc", TriggerAt: &at, TriggerRelation: EndTriggerRelation},
+		{Action: DisplayReminderAction, Description: "Desc", TriggerAt: &time.Time{}},
+	}
+
+	for _, reminder := range invalidReminders {
+		if reminder.valid() {
+			t.Errorf("Expected reminder to be invalid: %+v", reminder)
+		}
+	}
+}
+
+func TestTodoEndRelativeReminder(t *testing.T) {
+	todo := mockTodo()
+	todo.Reminders = []Reminder{{Action: DisplayReminderAction, Description: "Almost due", Trigger: -time.Hour, TriggerRelation: EndTriggerRelation}}
+	if todo.valid() {
+		t.Errorf("Expected todo without due date to reject an end relative reminder")
+	}
+
+	due := time.Date(2025, 3, 1, 17, 0, 0, 0, time.UTC)
+	todo.Due = &due
+	if !todo.valid() {
+		t.Errorf("Expected todo with due date to accept an end relative reminder")
+	}
+}
+
+func TestReminderRepeat(t *testing.T) {
+	repeat := 3
+	reminder := mockReminder()
+	reminder.Repeat = &repeat
+	var builder strings.Builder
+	if err := reminder.generate(&builder); err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	if !strings.Contains(builder.String(), "REPEAT:3\r\nDURATION:PT15M\r\n") {
+		t.Errorf("Expected REPEAT without interval to default to 15 minutes: %s", builder.String())
+	}
+
+	reminder.RepeatInterval = 5 * time.Minute
+	builder.Reset()
+	err := reminder.generate(&builder)
+	if err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	if !strings.Contains(builder.String(), "REPEAT:3\r\nDURATION:PT5M\r\n") {
+		t.Errorf("Expected REPEAT and DURATION in generated data: %s", builder.String())
+	}
+
+	noRepeat := 0
+	reminder = mockReminder()
+	reminder.Repeat = &noRepeat
+	builder.Reset()
+	if err := reminder.generate(&builder); err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	if strings.Contains(builder.String(), "REPEAT") || strings.Contains(builder.String(), "DURATION") {
+		t.Errorf("Expected no REPEAT or DURATION for zero repeats: %s", builder.String())
+	}
+
+	reminder = mockReminder()
+	reminder.RepeatInterval = time.Minute
+	if reminder.valid() {
+		t.Errorf("Expected reminder with interval but no REPEAT to be invalid")
+	}
+}
+
+func TestReminderAlarmExtensions(t *testing.T) {
+	reminder := mockReminder()
+	reminder.UID = "alarm-1@example.com"
+	reminder.Proximity = DepartProximity
+
+	at := time.Date(2025, 3, 1, 8, 50, 0, 0, time.UTC)
+	until := at.Add(5 * time.Minute)
+	snoozed, err := reminder.Snooze(at, until)
+	if err != nil {
+		t.Fatalf("Snooze() returned error: %v", err)
+	}
+	if reminder.Acknowledged == nil || !reminder.Acknowledged.Equal(at) {
+		t.Errorf("Expected Snooze() to acknowledge the reminder at %v, got %v", at, reminder.Acknowledged)
+	}
+	if snoozed.Snoozes != reminder.UID || snoozed.Proximity != DepartProximity || snoozed.TriggerAt == nil || !snoozed.TriggerAt.Equal(until) {
+		t.Errorf("Unexpected snooze reminder: %+v", snoozed)
+	}
+
+	var builder strings.Builder
+	if err := reminder.generate(&builder); err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	for _, want := range []string{"UID:alarm-1@example.com\r\n", "ACKNOWLEDGED:20250301T085000Z\r\n", "PROXIMITY:DEPART\r\n"} {
+		if !strings.Contains(builder.String(), want) {
+			t.Errorf("Expected %q in generated data: %s", want, builder.String())
+		}
+	}
+
+	builder.Reset()
+	if err := snoozed.generate(&builder); err != nil {
+		t.Fatalf("generate() returned error: %v", err)
+	}
+	for _, want := range []string{"TRIGGER;VALUE=DATE-TIME:20250301T085500Z\r\n", "RELATED-TO;RELTYPE=SNOOZE:alarm-1@example.com\r\n"} {
+		if !strings.Contains(builder.String(), want) {
+			t.Errorf("Expected %q in generated snooze data: %s", want, builder.String())
+		}
+	}
+
+	anonymous := mockReminder()
+	if _, err := anonymous.Snooze(at, until); err != ErrInvalidReminder {
+		t.Errorf("Snooze() without UID = %v, want %v", err, ErrInvalidReminder)
+	}
+
+	email := Reminder{UID: "alarm-2@example.com", Action: EmailReminderAction, Summary: "Soon", Description: "Soon",
+		Attendees: []Participant{{Name: "A", Email: "a@example.com"}}, Attachments: []Attachment{{URI: "https://example.com/agenda.pdf"}}}
+	snoozedEmail, err := email.Snooze(at, until)
+	if err != nil {
+		t.Fatalf("Snooze() returned error: %v", err)
+	}
+	if snoozedEmail.Summary != email.Summary {
+		t.Errorf("Snooze() Summary = %q, want %q", snoozedEmail.Summary, email.Summary)
+	}
+	snoozedEmail.Attendees[0].Name = "Changed"
+	snoozedEmail.Attachments[0].URI = "https://example.com/changed.pdf"
+	if email.Attendees[0].Name != "A" || email.Attachments[0].URI != "https://example.com/agenda.pdf" {
+		t.Errorf("Changing the snooze reminder changed the original: %+v", email)
+	}
+
+	reminder.Proximity = "NEARBY"
+	if reminder.valid() {
+		t.Errorf("Expected reminder with unknown PROXIMITY to be invalid")
+	}
+}
+
 func mockReminder() *Reminder {
 	return &Reminder{
 		Action:      DisplayReminderAction,
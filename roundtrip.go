@@ -0,0 +1,341 @@
+package ical
+
+import (
+	"slices"
+	"strings"
+)
+
+// What a component was read from, kept so generating it again keeps what the structs can not express:
+// unknown components, properties and parameters, and the order of properties.
+//
+// A property is written as it was read while the struct still generates the same lines for it as it did
+// when it was read. Once the struct changes, the property is written as the struct generates it.
+type source struct {
+	// The component as it was read
+	raw *rawComponent
+
+	// The component as the struct generated it right after it was read,
+	// nil when it could not be generated
+	baseline *rawComponent
+}
+
+// Components the structs generate inside of other components.
+// Other components are always kept as they were read.
+var modeledChildren = map[string]bool{"VALARM": true}
+
+// Properties added to a calendar read without them, as every calendar must have them
+var requiredCalendarProperties = map[string]bool{"VERSION": true, "PRODID": true}
+
+// Components the structs generate inside of a VCALENDAR, other than VTIMEZONE
+var modeledComponents = map[string]bool{"VEVENT": true, "VTODO": true, "VJOURNAL": true}
+
+// Creates the source of a component read from raw, generating the struct to compare with later
+func newSource(raw *rawComponent, generate func() (string, error)) *source {
+	s := &source{raw: raw}
+	if data, err := generate(); err == nil {
+		if components := readGenerated(data); len(components) > 0 {
+			s.baseline = components[0]
+		}
+	}
+	return s
+}
+
+// Reads generated components back into a tree, to merge them with what was read
+func readGenerated(data string) []*rawComponent {
+	calendar := readGeneratedCalendar(data)
+	if calendar == nil {
+		return nil
+	}
+	return calendar.Components
+}
+
+// Reads a generated calendar back into a tree
+func readGeneratedCalendar(data string) *rawComponent {
+	p := parser{lenient: true}
+	lines, _ := p.readLines(data)
+	calendars, err := p.buildTree(lines)
+	if err != nil {
+		return nil
+	}
+	return calendars[0]
+}
+
+// Merges the generated component with what it was read from
+//
+// The DTSTAMP that was read is kept, so an unchanged calendar is generated as it was read.
+func (s *source) merge(generated *rawComponent) *rawComponent {
+	current := linesByName(generated.Properties)
+	var baseline map[string][]string
+	if s.baseline != nil {
+		baseline = linesByName(s.baseline.Properties)
+	}
+	unchanged := func(name string) bool {
+		if name == "DTSTAMP" {
+			return true
+		}
+		if s.baseline == nil {
+			return len(current[name]) == 0
+		}
+		return slices.Equal(current[name], baseline[name])
+	}
+
+	merged := &rawComponent{Name: generated.Name, begin: s.raw.begin, end: s.raw.end}
+	written := make(map[string]bool)
+	for _, prop := range s.raw.Properties {
+		if unchanged(prop.Name) {
+			merged.Properties = append(merged.Properties, prop)
+			written[prop.Name] = true
+			continue
+		}
+		if !written[prop.Name] {
+			for _, generatedProp := range generated.properties(prop.Name) {
+				merged.Properties = append(merged.Properties, *generatedProp)
+			}
+			written[prop.Name] = true
+		}
+	}
+	for _, prop := range generated.Properties {
+		if !written[prop.Name] && !unchanged(prop.Name) {
+			merged.Properties = append(merged.Properties, prop)
+		}
+	}
+
+	if s.baseline != nil && sameComponents(s.baseline.Components, generated.Components) {
+		merged.Components = s.raw.Components
+		return merged
+	}
+	merged.Components = generated.Components
+	for _, child := range s.raw.Components {
+		if !modeledChildren[child.Name] {
+			merged.Components = append(merged.Components, child)
+		}
+	}
+	return merged
+}
+
+// The unfolded lines of the properties, by property name
+func linesByName(properties []rawProperty) map[string][]string {
+	lines := make(map[string][]string)
+	for _, prop := range properties {
+		lines[prop.Name] = append(lines[prop.Name], prop.source.text)
+	}
+	return lines
+}
+
+// Whether the components have the same unfolded lines
+func sameComponents(a, b []*rawComponent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !slices.Equal(a[i].lines(), b[i].lines()) {
+			return false
+		}
+	}
+	return true
+}
+
+// The unfolded lines of the component and the components in it
+func (c *rawComponent) lines() []string {
+	lines := []string{"BEGIN:" + c.Name}
+	for _, prop := range c.Properties {
+		lines = append(lines, prop.source.text)
+	}
+	for _, child := range c.Components {
+		lines = append(lines, child.lines()...)
+	}
+	return append(lines, "END:"+c.Name)
+}
+
+// Writes the component with the lines as they were read or generated
+func (c *rawComponent) write(builder *strings.Builder) {
+	c.writeProperties(builder)
+	for _, child := range c.Components {
+		child.write(builder)
+	}
+	builder.WriteString(c.endLine() + lineBreak)
+}
+
+// Writes the BEGIN line and the properties of the component
+func (c *rawComponent) writeProperties(builder *strings.Builder) {
+	if c.begin == "" {
+		builder.WriteString("BEGIN:" + c.Name + lineBreak)
+	} else {
+		builder.WriteString(c.begin + lineBreak)
+	}
+	for _, prop := range c.Properties {
+		builder.WriteString(prop.source.folded + lineBreak)
+	}
+}
+
+// The END line of the component
+func (c *rawComponent) endLine() string {
+	if c.end == "" {
+		return "END:" + c.Name
+	}
+	return c.end
+}
+
+// Adds the TZID parameters of the component and the components in it to tzids
+func (c *rawComponent) collectTZIDs(tzids map[string]bool) {
+	for _, prop := range c.Properties {
+		for _, tzid := range prop.params("TZID") {
+			tzids[tzid] = true
+		}
+	}
+	for _, child := range c.Components {
+		child.collectTZIDs(tzids)
+	}
+}
+
+// A component generated from a struct, and the source of the struct
+type generatedComponent struct {
+	components []*rawComponent
+	source     *source
+}
+
+// Reads the generated component back, merging the first component with the source of the struct
+func newGeneratedComponent(data string, s *source) generatedComponent {
+	components := readGenerated(data)
+	if s != nil && len(components) > 0 {
+		components[0] = s.merge(components[0])
+	}
+	return generatedComponent{components: components, source: s}
+}
+
+// Generates a calendar that was read from calendar data, keeping what the structs can not express
+//
+// Components are written in the order they were read, followed by new ones.
+// Components that were removed from the calendar are not written.
+// VTIMEZONEs are generated only for the time zones the calendar data did not define.
+func (c *Calendar) generateFromSource(opts generateOptions) ([]byte, error) {
+	var generated []generatedComponent
+	for i := range c.Events {
+		data, err := c.Events[i].generate(opts)
+		if err != nil {
+			return nil, err
+		}
+		generated = append(generated, newGeneratedComponent(data, c.Events[i].source))
+	}
+	for i := range c.Journals {
+		var builder strings.Builder
+		if err := c.Journals[i].generate(&builder, opts); err != nil {
+			return nil, err
+		}
+		generated = append(generated, newGeneratedComponent(builder.String(), c.Journals[i].source))
+	}
+	for i := range c.Todos {
+		var builder strings.Builder
+		if err := c.Todos[i].generate(&builder, opts); err != nil {
+			return nil, err
+		}
+		generated = append(generated, newGeneratedComponent(builder.String(), c.Todos[i].source))
+	}
+
+	// The components each component that was read became, in the order they were read
+	byRaw := make(map[*rawComponent][]*rawComponent)
+	var added []*rawComponent
+	for _, g := range generated {
+		if g.source != nil {
+			if _, found := byRaw[g.source.raw]; !found {
+				byRaw[g.source.raw] = g.components
+				continue
+			}
+		}
+		added = append(added, g.components...)
+	}
+
+	var components []*rawComponent
+	defined := make(map[string]bool)
+	for _, child := range c.source.raw.Components {
+		switch {
+		case modeledComponents[child.Name]:
+			components = append(components, byRaw[child]...)
+			delete(byRaw, child)
+		case child.Name == "VTIMEZONE":
+			if tzid, found := child.property("TZID"); found {
+				defined[tzid.Value] = true
+			}
+			components = append(components, child)
+		default:
+			components = append(components, child)
+		}
+	}
+	// Components read from other calendar data, such as another calendar, are new to this one
+	for _, g := range generated {
+		if g.source != nil && byRaw[g.source.raw] != nil {
+			components = append(components, byRaw[g.source.raw]...)
+			delete(byRaw, g.source.raw)
+		}
+	}
+	components = append(components, added...)
+
+	referenced := make(map[string]bool)
+	for _, component := range components {
+		if modeledComponents[component.Name] {
+			component.collectTZIDs(referenced)
+		}
+	}
+
+	calendar := &rawComponent{Name: "VCALENDAR", begin: c.source.raw.begin, end: c.source.raw.end}
+	calendar.Properties = c.source.merge(c.generateProperties()).Properties
+	for _, line := range calendarProperties {
+		cl, _ := parseContentLine(line)
+		if _, found := c.source.raw.property(cl.Name); !found && requiredCalendarProperties[cl.Name] {
+			calendar.Properties = append(calendar.Properties, rawProperty{contentLine: cl, source: &rawLine{text: line, folded: line}})
+		}
+	}
+
+	var builder strings.Builder
+	calendar.writeProperties(&builder)
+	err := c.generateTimeZonesFor(&builder, opts, func(tzid string) bool {
+		return referenced[tzid] && !defined[tzid]
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, component := range components {
+		component.write(&builder)
+	}
+	builder.WriteString(calendar.endLine() + lineBreak)
+	return []byte(builder.String()), nil
+}
+
+// The properties of a parsed calendar the struct has fields for, to merge with the ones that were read
+//
+// Name and Description are written as the properties the calendar was read with, NAME or X-WR-CALNAME
+// and DESCRIPTION or X-WR-CALDESC, or as X-WR-CALNAME and X-WR-CALDESC when it had neither.
+func (c *Calendar) generateProperties() *rawComponent {
+	var builder strings.Builder
+	builder.WriteString("BEGIN:VCALENDAR" + lineBreak)
+	for _, field := range []struct {
+		value string
+		names []string
+	}{
+		{c.Name, []string{"NAME", "X-WR-CALNAME"}},
+		{c.Description, []string{"DESCRIPTION", "X-WR-CALDESC"}},
+	} {
+		if field.value == "" {
+			continue
+		}
+		var names []string
+		for _, name := range field.names {
+			if _, found := c.source.raw.property(name); found {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			names = field.names[1:]
+		}
+		for _, name := range names {
+			builder.WriteString(foldLine(name+":"+escapeText(field.value)) + lineBreak)
+		}
+	}
+	builder.WriteString("END:VCALENDAR" + lineBreak)
+
+	generated := readGeneratedCalendar(builder.String())
+	if generated == nil {
+		return &rawComponent{Name: "VCALENDAR"}
+	}
+	return generated
+}
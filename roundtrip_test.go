@@ -0,0 +1,235 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func roundTripCalendar(t *testing.T, lines ...string) *Calendar {
+	t.Helper()
+	cal, err := Parse(strings.NewReader(calendarData(lines...)))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	return cal
+}
+
+func generateString(t *testing.T, cal *Calendar) string {
+	t.Helper()
+	data, err := cal.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	return string(data)
+}
+
+func TestMergeSource(t *testing.T) {
+	cal := roundTripCalendar(t,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VTODO",
+		"UID:a@example.com",
+		"X-FIRST:1",
+		"SUMMARY;X-LABEL=kept:Write",
+		"DESCRIPTION:Before",
+		"DTSTAMP:20250101T000000Z",
+		"PRIORITY:3",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Soon",
+		"TRIGGER;X-VENDOR=1:-PT5M",
+		"END:VALARM",
+		"BEGIN:X-NOTE",
+		"X-TEXT:kept",
+		"END:X-NOTE",
+		"END:VTODO",
+		"END:VCALENDAR",
+	)
+	todo := &cal.Todos[0]
+	todo.Description = "After"
+	todo.Priority = nil
+	percent := 50
+	todo.PercentComplete = &percent
+	todo.Reminders[0].Trigger = -10 * time.Minute
+
+	output := generateString(t, cal)
+	expected := calendarData(
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VTODO",
+		"UID:a@example.com",
+		"X-FIRST:1",
+		"SUMMARY;X-LABEL=kept:Write",
+		"DESCRIPTION:After",
+		"DTSTAMP:20250101T000000Z",
+		"PERCENT-COMPLETE:50",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Soon",
+		"TRIGGER:-PT10M",
+		"END:VALARM",
+		"BEGIN:X-NOTE",
+		"X-TEXT:kept",
+		"END:X-NOTE",
+		"END:VTODO",
+		"END:VCALENDAR",
+	)
+	if output != expected {
+		t.Errorf("Generate() =\n%s\nwant\n%s", output, expected)
+	}
+}
+
+func TestGenerateFromSourceComponents(t *testing.T) {
+	cal := roundTripCalendar(t,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VJOURNAL",
+		"UID:first@example.com",
+		"SUMMARY:First",
+		"DESCRIPTION:Kept",
+		"END:VJOURNAL",
+		"BEGIN:X-VENDOR",
+		"X-DATA:kept",
+		"END:X-VENDOR",
+		"BEGIN:VJOURNAL",
+		"UID:second@example.com",
+		"SUMMARY:Second",
+		"DESCRIPTION:Removed",
+		"END:VJOURNAL",
+		"END:VCALENDAR",
+	)
+	cal.Journals = cal.Journals[:1]
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	err := cal.AddEvent(Event{UID: "new@example.com", Title: "New", StartDate: start, EndDate: start.Add(time.Hour), TimeZone: "Europe/Paris"})
+	if err != nil {
+		t.Fatalf("AddEvent() returned error: %v", err)
+	}
+
+	output := generateString(t, cal)
+	first := strings.Index(output, "UID:first@example.com")
+	vendor := strings.Index(output, "BEGIN:X-VENDOR")
+	added := strings.Index(output, "UID:new@example.com")
+	if first < 0 || vendor < first || added < vendor {
+		t.Errorf("Generate() = %s, want the journal, the X-VENDOR component and the new event in order", output)
+	}
+	if strings.Contains(output, "second@example.com") {
+		t.Errorf("Generate() = %s, want the removed journal entry left out", output)
+	}
+	timeZone := strings.Index(output, "BEGIN:VTIMEZONE\r\nTZID:Europe/Paris")
+	if timeZone < 0 || timeZone > first {
+		t.Errorf("Generate() = %s, want a VTIMEZONE for the new event before the components", output)
+	}
+}
+
+func TestGenerateFromSourceInvalidBaseline(t *testing.T) {
+	// Without a SUMMARY the journal entry can not be generated when it is read
+	cal := roundTripCalendar(t,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VJOURNAL",
+		"UID:a@example.com",
+		"DESCRIPTION:Notes",
+		"X-KEPT:yes",
+		"END:VJOURNAL",
+		"END:VCALENDAR",
+	)
+	if _, err := cal.Generate(); err == nil {
+		t.Fatalf("Generate() = nil error, want the missing summary")
+	}
+
+	cal.Journals[0].Summary = "Fixed"
+	output := generateString(t, cal)
+	for _, expected := range []string{"SUMMARY:Fixed\r\n", "X-KEPT:yes\r\n", "DESCRIPTION:Notes\r\n"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generate() = %s, want it to contain %q", output, expected)
+		}
+	}
+	if strings.Count(output, "DESCRIPTION") != 1 {
+		t.Errorf("Generate() = %s, want a single DESCRIPTION", output)
+	}
+}
+
+func TestGenerateFromSourceMissingProperties(t *testing.T) {
+	cal, _, err := ParseLenient(strings.NewReader(calendarData("BEGIN:VTODO", "UID:a", "SUMMARY:Orphan", "END:VTODO")))
+	if err != nil {
+		t.Fatalf("ParseLenient() returned error: %v", err)
+	}
+	output := generateString(t, cal)
+	if !strings.HasPrefix(output, calendarData("BEGIN:VCALENDAR", calendarProperties[0], calendarProperties[1], "BEGIN:VTODO")) {
+		t.Errorf("Generate() = %s, want VERSION and PRODID added", output)
+	}
+}
+
+func TestGenerateFromSourceCalendarProperties(t *testing.T) {
+	parse := func(properties ...string) *Calendar {
+		lines := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Example//EN"}, properties...)
+		return roundTripCalendar(t, append(lines, "BEGIN:VTODO", "UID:a", "SUMMARY:A", "END:VTODO", "END:VCALENDAR")...)
+	}
+
+	var tests = []struct {
+		name       string
+		cal        *Calendar
+		change     func(cal *Calendar)
+		expected   []string
+		unexpected []string
+	}{
+		{"unchanged", parse("X-WR-CALNAME:Team", "X-WR-CALDESC:Team\\, work"), func(cal *Calendar) {},
+			[]string{"X-WR-CALNAME:Team\r\n", "X-WR-CALDESC:Team\\, work\r\n"}, nil},
+		{"name", parse("X-WR-CALNAME:Team", "X-WR-CALDESC:Work"), func(cal *Calendar) { cal.Name = "New" },
+			[]string{"X-WR-CALNAME:New\r\n", "X-WR-CALDESC:Work\r\n"}, []string{"Team"}},
+		{"both names", parse("NAME:Team", "X-WR-CALNAME:Team"), func(cal *Calendar) { cal.Name = "New, team" },
+			[]string{"NAME:New\\, team\r\n", "X-WR-CALNAME:New\\, team\r\n"}, []string{":Team"}},
+		{"description", parse("DESCRIPTION:Work"), func(cal *Calendar) { cal.Description = "Home" },
+			[]string{"DESCRIPTION:Home\r\n"}, []string{"X-WR-CALDESC", "Work"}},
+		{"added", parse(), func(cal *Calendar) { cal.Name, cal.Description = "New", "Home" },
+			[]string{"X-WR-CALNAME:New\r\n", "X-WR-CALDESC:Home\r\n"}, nil},
+		{"removed", parse("X-WR-CALNAME:Team"), func(cal *Calendar) { cal.Name = "" },
+			nil, []string{"X-WR-CALNAME"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change(tt.cal)
+			output := generateString(t, tt.cal)
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Generate() = %s, want it to contain %q", output, expected)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(output, unexpected) {
+					t.Errorf("Generate() = %s, want it not to contain %q", output, unexpected)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateFromSourceWithoutSummary(t *testing.T) {
+	cal := roundTripCalendar(t,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Example//EN",
+		"BEGIN:VEVENT",
+		"UID:busy@example.com",
+		"DTSTART:20250106T140000Z",
+		"DTEND:20250106T153000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	cal.Events[0].EndDate = cal.Events[0].EndDate.Add(time.Hour)
+
+	output := generateString(t, cal)
+	if strings.Contains(output, "SUMMARY") || !strings.Contains(output, "DTEND;TZID=UTC:20250106T163000\r\n") {
+		t.Errorf("Generate() = %s, want the changed DTEND and no SUMMARY", output)
+	}
+
+	if err := (&Event{UID: "a", StartDate: time.Now(), EndDate: time.Now().Add(time.Hour), TimeZone: "UTC"}).Validate(); err == nil {
+		t.Errorf("Validate() = nil, want a Title required for events created in code")
+	}
+}
@@ -0,0 +1,107 @@
+package ical
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time for a Scheduler.
+//
+// Replace it to control time in tests.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+}
+
+// The Clock of the operating system
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// The default time window computed by a Scheduler at once
+const defaultLookahead = 24 * time.Hour
+
+// Scheduler delivers the alarms of a calendar on a channel, when they fire.
+//
+// The calendar must not be modified while the scheduler is running.
+type Scheduler struct {
+	// REQUIRED: Calendar to deliver alarms for
+	Calendar *Calendar
+
+	// REQUIRED: Source of time
+	Clock Clock
+
+	// OPTIONAL: How far ahead alarms are computed at once
+	//
+	// Defaults to 24 hours
+	Lookahead time.Duration
+}
+
+// NewScheduler creates a Scheduler for the calendar.
+//
+// If clock is nil, the system clock is used.
+func NewScheduler(cal *Calendar, clock Clock) *Scheduler {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &Scheduler{Calendar: cal, Clock: clock}
+}
+
+// Run delivers every alarm that fires from now on, in order, until the context is cancelled.
+//
+// The returned channel is closed when the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) <-chan Alarm {
+	alarms := make(chan Alarm)
+	go func() {
+		defer close(alarms)
+
+		lookahead := s.Lookahead
+		if lookahead <= 0 {
+			lookahead = defaultLookahead
+		}
+
+		from := s.Clock.Now()
+		for {
+			to := from.Add(lookahead)
+			for _, alarm := range s.Calendar.Alarms(from, to) {
+				if !s.waitUntil(ctx, alarm.FireTime) {
+					return
+				}
+				select {
+				case alarms <- alarm:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !s.waitUntil(ctx, to) {
+				return
+			}
+			from = to
+		}
+	}()
+	return alarms
+}
+
+// Waits until the clock reaches t. Returns false if the context was cancelled first.
+func (s *Scheduler) waitUntil(ctx context.Context, t time.Time) bool {
+	for {
+		d := t.Sub(s.Clock.Now())
+		if d <= 0 {
+			return ctx.Err() == nil
+		}
+		select {
+		case <-s.Clock.After(d):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ical
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
+)
+
+// A clock that jumps forward instead of sleeping
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestSchedulerRun(t *testing.T) {
+	start := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+	cal := Create("Scheduled", "Calendar with reminders")
+	cal.Events = []Event{
+		{
+			Title: "Past", StartDate: start.Add(-time.Hour), EndDate: start, TimeZone: TimeZone(timezones.UTC),
+			Reminders: []Reminder{{Action: DisplayReminderAction, Description: "Missed", Trigger: -time.Minute}},
+		},
+		{
+			Title: "Tomorrow", StartDate: start.Add(26 * time.Hour), EndDate: start.Add(27 * time.Hour), TimeZone: TimeZone(timezones.UTC),
+			Reminders: []Reminder{{Action: DisplayReminderAction, Description: "Second", Trigger: -time.Hour}},
+		},
+		{
+			Title: "Today", StartDate: start.Add(2 * time.Hour), EndDate: start.Add(3 * time.Hour), TimeZone: TimeZone(timezones.UTC),
+			Reminders: []Reminder{{Action: DisplayReminderAction, Description: "First", Trigger: -30 * time.Minute}},
+		},
+	}
+
+	clock := &fakeClock{now: start}
+	scheduler := NewScheduler(cal, clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	alarms := scheduler.Run(ctx)
+	first := <-alarms
+	if first.Reminder.Description != "First" || !first.FireTime.Equal(start.Add(90*time.Minute)) {
+		t.Errorf("First alarm = %q at %v", first.Reminder.Description, first.FireTime)
+	}
+	if clock.Now().Before(first.FireTime) {
+		t.Errorf("Alarm delivered at %v, before its fire time %v", clock.Now(), first.FireTime)
+	}
+
+	second := <-alarms
+	if second.Reminder.Description != "Second" || !second.FireTime.Equal(start.Add(25*time.Hour)) {
+		t.Errorf("Second alarm = %q at %v", second.Reminder.Description, second.FireTime)
+	}
+
+	cancel()
+	for range alarms {
+	}
+}
+
+func TestNewSchedulerDefaultClock(t *testing.T) {
+	scheduler := NewScheduler(mockCalendar(), nil)
+	if _, ok := scheduler.Clock.(systemClock); !ok {
+		t.Errorf("Expected NewScheduler() to default to the system clock, got %T", scheduler.Clock)
+	}
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	ical "github.com/Tylerchristensen100/iCal"
+)
+
+// The rules the structs can not express that ParseLenient reports in the exports
+var expectedDiagnostics = map[string][]string{
+	"google.ics": {`line 30, column 56: unsupported RRULE BYDAY "MO,WE,FR" with more than one day, read only MO`},
+}
+
+// Exports of popular calendar applications, generated again without changes must be unchanged
+func TestRoundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.ics"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no calendars in testdata: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("ReadFile() returned error: %v", err)
+			}
+			cal, diagnostics, err := ical.ParseLenient(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("ParseLenient() returned error: %v", err)
+			}
+			var messages []string
+			for _, d := range diagnostics {
+				messages = append(messages, d.String())
+			}
+			expected := expectedDiagnostics[filepath.Base(file)]
+			if !slices.Equal(messages, expected) {
+				t.Errorf("ParseLenient() diagnostics = %q, want %q", messages, expected)
+			}
+			// Rules the structs can not express are valid, so Parse reads them without a problem
+			if _, err := ical.Parse(bytes.NewReader(data)); err != nil {
+				t.Errorf("Parse() returned error: %v", err)
+			}
+			generated, err := cal.Generate()
+			if err != nil {
+				t.Fatalf("Generate() returned error: %v", err)
+			}
+			if string(generated) != string(data) {
+				t.Errorf("Generate() changed the calendar:\n%s", lineDiff(string(data), string(generated)))
+			}
+		})
+	}
+}
+
+func TestRoundTripChanged(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "google.ics"))
+	if err != nil {
+		t.Fatalf("ReadFile() returned error: %v", err)
+	}
+	cal, _, err := ical.ParseLenient(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseLenient() returned error: %v", err)
+	}
+
+	cal.Events[0].Title = "Daily standup"
+	cal.Events = cal.Events[:1]
+	err = cal.AddTodo(ical.Todo{UID: "agenda@example.com", Summary: "Write the agenda"})
+	if err != nil {
+		t.Fatalf("AddTodo() returned error: %v", err)
+	}
+
+	generated, err := cal.Generate()
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	output := string(generated)
+
+	for _, expected := range []string{
+		"X-WR-CALNAME:Team\r\n",
+		"PRODID:-//Google Inc//Google Calendar 70.9054//EN\r\n",
+		"SUMMARY:Daily standup\r\n",
+		"X-GOOGLE-CONFERENCE:https://meet.google.com/abc-defg-hij\r\n",
+		"RRULE:FREQ=WEEKLY;WKST=SU;UNTIL=20250630T163000Z;BYDAY=MO,WE,FR\r\n",
+		"e;X-NUM-GUESTS=0:mailto:jane@example.com\r\n",
+		"UID:agenda@example.com\r\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generate() = %s, want it to contain %q", output, expected)
+		}
+	}
+	for _, unexpected := range []string{"SUMMARY:Standup\r\n", "SUMMARY:Offsite", "BEGIN:VTIMEZONE\r\nTZID:UTC"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Generate() = %s, want it not to contain %q", output, unexpected)
+		}
+	}
+
+	// The changed SUMMARY stays where it was read
+	if strings.Index(output, "SUMMARY:Daily standup") > strings.Index(output, "TRANSP:OPAQUE") {
+		t.Errorf("Generate() moved SUMMARY after TRANSP: %s", output)
+	}
+	if _, diagnostics, err := ical.ParseLenient(bytes.NewReader(generated)); err != nil || len(diagnostics) != 1 {
+		t.Errorf("ParseLenient() of the generated calendar = %v, %v, want only the BYDAY of the export", diagnostics, err)
+	}
+}
+
+// The first line that differs, for test failures
+func lineDiff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\r\n"), strings.Split(got, "\r\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n want %s\n got  %s", i+1, w, g)
+		}
+	}
+	return ""
+}
@@ -6,6 +6,16 @@ func timeToICal(t time.Time) string {
 	return t.Format(iCalTimeLayout)
 }
 
+// A date-time property in the time zone, e.g. "DUE;TZID=America/New_York:20250106T100000"
+//
+// Without a time zone the time is written as a floating local time.
+func zonedTime(name string, t time.Time, tz TimeZone, opts generateOptions) string {
+	if tz == "" {
+		return name + ":" + timeToICal(t)
+	}
+	return name + ";TZID=" + paramValue(opts.tzid(tz)) + ":" + timeToICal(tz.localTime(t))
+}
+
 func stripDay(t time.Time) time.Time {
 	return time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 }
@@ -1,10 +1,23 @@
 package ical
 
-import timezones "github.com/Tylerchristensen100/iCal/timezones"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	timezones "github.com/Tylerchristensen100/iCal/timezones"
+)
 
 // TimeZone represents the time zone for an event.
 // It includes all valid time zones defined by the IANA Time Zone Database.
-// All TimeZones are copied from https://github.com/Tylerchristensen100/iCal_VTIMEZONE
+//
+// Definitions are generated from the time zone database of the system,
+// so any zone time.LoadLocation knows can be used. When the database is not available,
+// the definitions copied from https://github.com/Tylerchristensen100/iCal_VTIMEZONE are used instead.
+// With a source set by timezones.SetSource, only the zones of that source can be used.
 //
 // iCalendar VTIMEZONE component
 type TimeZone timezones.TZID
@@ -19,7 +32,132 @@ func (tz *TimeZone) iCal() (string, bool) {
 		return string(timezones.UTC), false
 	}
 
-	return timezones.Get(timezones.TZID(*tz))
+	data, err := timezones.Get(timezones.TZID(*tz))
+	if err != nil {
+		return "", false
+	}
+	return data, true
+}
+
+// Return the TimeZone Definition, covering the observances in effect between from and to.
+//
+// See timezones.Definition for where definitions come from.
+//
+// Returns an error wrapping ErrInvalidTimeZone when the time zone is not known,
+// or the error of a time zone source that failed to load.
+func (tz *TimeZone) definition(from, to time.Time) (string, error) {
+	if err := tz.check(); err != nil {
+		return "", err
+	}
+	return timezones.Definition(timezones.TZID(*tz), from, to)
+}
+
+// TimeZoneFromLocation returns the TimeZone of a *time.Location.
+//
+// A nil location is UTC. time.Local is resolved through the TZ environment variable
+// or the /etc/localtime link, since its name is not an IANA identifier.
+func TimeZoneFromLocation(loc *time.Location) (TimeZone, error) {
+	if loc == nil {
+		return TimeZone(timezones.UTC), nil
+	}
+
+	name := loc.String()
+	if loc == time.Local || name == "Local" {
+		name = localZoneName()
+	}
+
+	tz := TimeZone(name)
+	if err := tz.check(); err != nil {
+		return "", err
+	}
+	return tz, nil
+}
+
+// ParseTimeZone normalizes a TZID found in the wild to an IANA time zone.
+//
+// Besides IANA names, Windows names ("Eastern Standard Time"), Windows display names
+// ("(UTC-05:00) Eastern Time (US & Canada)") and prefixed names
+// ("/mozilla.org/20050126_1/America/New_York") are understood.
+//
+// Returns an error wrapping ErrInvalidTimeZone when the name is not known,
+// or the error of a time zone source that failed to load.
+func ParseTimeZone(name string) (TimeZone, error) {
+	tzid, err := timezones.Resolve(name)
+	if err == nil {
+		return TimeZone(tzid), nil
+	}
+	if !errors.Is(err, timezones.ErrUnknownTimeZone) {
+		return "", err
+	}
+
+	tz := TimeZone(strings.TrimSpace(name))
+	if err := tz.check(); err != nil {
+		return "", err
+	}
+	return tz, nil
+}
+
+// WindowsName returns the Windows time zone name used by Outlook and Exchange,
+// e.g. "Eastern Standard Time" for "America/New_York".
+func (tz *TimeZone) WindowsName() (string, bool) {
+	return timezones.WindowsName(timezones.TZID(*tz))
+}
+
+// Location loads the *time.Location of the TimeZone from the time zone database of the system.
+func (tz *TimeZone) Location() (*time.Location, error) {
+	if err := tz.check(); err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(tz.ID())
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s': %w", ErrInvalidTimeZone, tz.ID(), err)
+	}
+	return loc, nil
+}
+
+// Converts t into the time zone, so its wall-clock time can be written with TZID.
+//
+// If the zone can not be loaded, t is returned unchanged.
+func (tz *TimeZone) localTime(t time.Time) time.Time {
+	loc, err := tz.Location()
+	if err != nil {
+		return t
+	}
+	return t.In(loc)
+}
+
+// Converts a date, e.g. an exception date from CancelOnDate, into the time zone.
+//
+// A time at midnight is a date and keeps its day, any other time is an instant.
+func (tz *TimeZone) localDate(t time.Time) time.Time {
+	if t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 || t.Nanosecond() != 0 {
+		return tz.localTime(t)
+	}
+	loc, err := tz.Location()
+	if err != nil {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// The IANA name of the local time zone, "Local" if it can not be found
+func localZoneName() string {
+	if tz, set := os.LookupEnv("TZ"); set {
+		name := strings.TrimPrefix(tz, ":")
+		if name == "" {
+			return string(timezones.UTC)
+		}
+		if !filepath.IsAbs(name) {
+			return name
+		}
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, name, found := strings.Cut(target, "zoneinfo/"); found {
+			return name
+		}
+	}
+	return "Local"
 }
 
 // Return the ID of the TimeZone
@@ -30,9 +168,44 @@ func (tz *TimeZone) ID() string {
 }
 
 func (tz *TimeZone) valid() bool {
+	return tz.check() == nil
+}
+
+// Returns an error wrapping ErrInvalidTimeZone when the time zone is not known,
+// or the error of a time zone source that failed to load
+func (tz *TimeZone) check() error {
 	if tz == nil {
-		return false
+		return fmt.Errorf("%w: ''", ErrInvalidTimeZone)
+	}
+	if *tz == "" || *tz == "Local" {
+		return fmt.Errorf("%w: '%s'", ErrInvalidTimeZone, tz.ID())
 	}
 	zone := timezones.TZID(*tz)
-	return zone.Valid()
+	valid, err := zone.Valid()
+	if valid {
+		return nil
+	}
+	// The time zone database of the system is used before the embedded source, see timezones.Definition.
+	// A source set with timezones.SetSource is the only one used.
+	if !timezones.CustomSource() {
+		if _, loadErr := time.LoadLocation(string(zone)); loadErr == nil {
+			return nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("time zone '%s' could not be loaded: %w", zone, err)
+	}
+	return fmt.Errorf("%w: '%s'", ErrInvalidTimeZone, zone)
+}
+
+// Records a ValidationError at path when the time zone is not known, or its source failed to load
+func (v *validation) checkTimeZone(tz TimeZone, path string) {
+	err := tz.check()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrInvalidTimeZone):
+		v.checkErr(false, ErrInvalidTimeZone, path, "is not a known time zone", tz)
+	default:
+		v.checkErr(false, err, path, "could not be loaded", tz)
+	}
 }
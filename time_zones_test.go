@@ -1,7 +1,11 @@
 package ical
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/Tylerchristensen100/iCal/timezones"
 )
@@ -47,3 +51,152 @@ func TestTimeZoneToICS(t *testing.T) {
 		t.Errorf("Expected ICS string for invalid timezone %q to be UTC, got %q", invalidTimezone, icsString)
 	}
 }
+
+func TestTimeZoneDefinition(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	timezone := TimeZone(timezones.America_New_York)
+	definition, err := timezone.definition(from, to)
+	if err != nil {
+		t.Fatalf("Expected to find timezone definition for %q, got %v", timezone, err)
+	}
+	if strings.Contains(definition, "COMMENT:") || !strings.Contains(definition, "RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU") {
+		t.Errorf("Expected generated definition for %q, got %q", timezone, definition)
+	}
+
+	invalidTimezone := TimeZone("Invalid/Timezone")
+	if _, err := invalidTimezone.definition(from, to); !errors.Is(err, ErrInvalidTimeZone) {
+		t.Errorf("Expected %v for invalid timezone %q, got %v", ErrInvalidTimeZone, invalidTimezone, err)
+	}
+}
+
+func TestTimeZoneLocation(t *testing.T) {
+	timezone := TimeZone(timezones.America_New_York)
+	loc, err := timezone.Location()
+	if err != nil {
+		t.Fatalf("Location() returned error: %v", err)
+	}
+	if loc.String() != "America/New_York" {
+		t.Errorf("Location() = %q; want %q", loc, "America/New_York")
+	}
+
+	for _, invalid := range []TimeZone{"", "Invalid/Timezone", "Local"} {
+		if _, err := invalid.Location(); !errors.Is(err, ErrInvalidTimeZone) {
+			t.Errorf("Location(%q) error = %v; want %v", invalid, err, ErrInvalidTimeZone)
+		}
+	}
+}
+
+func TestTimeZoneFromLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() returned error: %v", err)
+	}
+
+	tests := []struct {
+		loc      *time.Location
+		expected TimeZone
+	}{
+		{berlin, TimeZone(timezones.Europe_Berlin)},
+		{time.UTC, TimeZone(timezones.UTC)},
+		{nil, TimeZone(timezones.UTC)},
+	}
+	for _, tt := range tests {
+		tz, err := TimeZoneFromLocation(tt.loc)
+		if err != nil || tz != tt.expected {
+			t.Errorf("TimeZoneFromLocation(%v) = %q, %v; want %q", tt.loc, tz, err, tt.expected)
+		}
+	}
+
+	if _, err := TimeZoneFromLocation(time.FixedZone("Custom", 3600)); !errors.Is(err, ErrInvalidTimeZone) {
+		t.Errorf("TimeZoneFromLocation(fixed zone) error = %v; want %v", err, ErrInvalidTimeZone)
+	}
+
+	t.Setenv("TZ", "Asia/Tokyo")
+	if tz := TimeZone(localZoneName()); tz != TimeZone(timezones.Asia_Tokyo) {
+		t.Errorf("localZoneName() with TZ set = %q; want %q", tz, timezones.Asia_Tokyo)
+	}
+}
+
+func TestParseTimeZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected TimeZone
+		success  bool
+	}{
+		{"America/New_York", TimeZone(timezones.America_New_York), true},
+		{"Eastern Standard Time", TimeZone(timezones.America_New_York), true},
+		{"(UTC-06:00) Central Time (US & Canada)", TimeZone(timezones.America_Chicago), true},
+		{"/mozilla.org/20050126_1/Europe/Paris", TimeZone(timezones.Europe_Paris), true},
+		{"Invalid/Timezone", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		tz, err := ParseTimeZone(tt.name)
+		if (err == nil) != tt.success || tz != tt.expected {
+			t.Errorf("ParseTimeZone(%q) = %q, %v; want %q, success %v", tt.name, tz, err, tt.expected, tt.success)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidTimeZone) {
+			t.Errorf("ParseTimeZone(%q) error = %v; want %v", tt.name, err, ErrInvalidTimeZone)
+		}
+	}
+}
+
+func TestTimeZoneWindowsName(t *testing.T) {
+	timezone := TimeZone(timezones.Europe_Paris)
+	if name, found := timezone.WindowsName(); !found || name != "Romance Standard Time" {
+		t.Errorf("WindowsName(%q) = %q, %v; want %q", timezone, name, found, "Romance Standard Time")
+	}
+}
+
+// A time zone source that can not be read
+type failingSource struct{}
+
+var errSourceUnavailable = errors.New("source unavailable")
+
+func (failingSource) Lookup(timezones.TZID) (string, error) {
+	return "", errSourceUnavailable
+}
+
+func TestTimeZoneSourceFailure(t *testing.T) {
+	cal := mockCalendar()
+	timezones.SetSource(failingSource{})
+	t.Cleanup(func() { timezones.SetSource(nil) })
+
+	if _, err := ParseTimeZone("America/New_York"); !errors.Is(err, errSourceUnavailable) {
+		t.Errorf("ParseTimeZone() error = %v; want %v", err, errSourceUnavailable)
+	}
+
+	todo := Todo{UID: "a", Summary: "A", TimeZone: "Custom/Zone"}
+	err := todo.Validate()
+	var validationErr *ValidationError
+	if !errors.Is(err, errSourceUnavailable) || !errors.As(err, &validationErr) || validationErr.Path != "TimeZone" {
+		t.Errorf("Validate() = %v; want the source error at TimeZone", err)
+	}
+
+	if _, err := cal.Generate(); !errors.Is(err, errSourceUnavailable) {
+		t.Errorf("Generate() error = %v; want %v", err, errSourceUnavailable)
+	}
+}
+
+func TestTimeZoneCustomSource(t *testing.T) {
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	cal := Create("Custom", "A calendar with a custom time zone source")
+	cal.AddEvent(Event{UID: "a@example.com", Title: "A", StartDate: start, EndDate: start.Add(time.Hour), TimeZone: TimeZone(timezones.America_New_York)})
+
+	// The system time zone database must not stand in for a source without the zone
+	timezones.SetSource(timezones.FromFS(fstest.MapFS{}))
+	t.Cleanup(func() { timezones.SetSource(nil) })
+
+	zone := TimeZone(timezones.America_New_York)
+	if err := zone.check(); !errors.Is(err, ErrInvalidTimeZone) {
+		t.Errorf("check() = %v; want %v", err, ErrInvalidTimeZone)
+	}
+	if err := cal.Validate(); !errors.Is(err, ErrInvalidTimeZone) {
+		t.Errorf("Validate() = %v; want %v", err, ErrInvalidTimeZone)
+	}
+	if data, err := cal.Generate(); !errors.Is(err, ErrInvalidTimeZone) {
+		t.Errorf("Generate() = %q, %v; want %v", data, err, ErrInvalidTimeZone)
+	}
+}
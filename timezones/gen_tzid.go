@@ -0,0 +1,102 @@
+//go:build ignore
+
+// Rebuilds tzid.go from the embedded time zone definitions.
+//
+// Run with `go generate` from the timezones directory.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	dataFile   = "timezones.json.gz"
+	outputFile = "tzid.go"
+)
+
+func main() {
+	ids, err := readIDs(dataFile)
+	if err != nil {
+		log.Fatalf("gen_tzid: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_tzid.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package timezones\n\n")
+	buf.WriteString("// iCalendar VTIMEZONE component\n")
+	buf.WriteString("type TZID string\n\n")
+	buf.WriteString("const (\n")
+
+	names := make(map[string]string)
+	for _, id := range ids {
+		name := constantName(id)
+		if other, exists := names[name]; exists {
+			log.Fatalf("gen_tzid: %q and %q both map to %s", other, id, name)
+		}
+		names[name] = id
+		fmt.Fprintf(&buf, "\t%s TZID = %q\n", name, id)
+	}
+	buf.WriteString(")\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("gen_tzid: failed to format source: %v", err)
+	}
+	if err := os.WriteFile(outputFile, source, 0o644); err != nil {
+		log.Fatalf("gen_tzid: %v", err)
+	}
+}
+
+// The sorted time zone identifiers of the embedded data
+func readIDs(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer reader.Close()
+
+	var timezones map[string]string
+	if err := json.NewDecoder(reader).Decode(&timezones); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON data: %w", err)
+	}
+
+	ids := make([]string, 0, len(timezones))
+	for id := range timezones {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// The Go identifier of a time zone, e.g. "America/Port-au-Prince" to America_Port_au_Prince
+// and "Etc/GMT+5" to Etc_GMT_add_5
+func constantName(id string) string {
+	var builder strings.Builder
+	for i, r := range id {
+		switch {
+		case r == '+':
+			builder.WriteString("_add_")
+		case r == '-' && i+1 < len(id) && id[i+1] >= '0' && id[i+1] <= '9':
+			builder.WriteString("_subtract_")
+		case r == '/' || r == '-':
+			builder.WriteByte('_')
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,285 @@
+package timezones
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	lineBreak      = "\r\n"
+	iCalTimeLayout = "20060102T150405"
+	maxLineOctets  = 75
+)
+
+// A change of UTC offset in a time zone
+type transition struct {
+	// Instant of the change
+	at time.Time
+
+	offsetFrom int
+	offsetTo   int
+	name       string
+	dst        bool
+}
+
+// Local time of the transition, in the offset that was in effect before it
+func (t transition) local() time.Time {
+	return t.at.UTC().Add(time.Duration(t.offsetFrom) * time.Second)
+}
+
+// The kind of observance a transition starts
+type observanceKey struct {
+	dst        bool
+	offsetFrom int
+	offsetTo   int
+	name       string
+}
+
+// A STANDARD or DAYLIGHT sub-component
+type observance struct {
+	key   observanceKey
+	start transition
+
+	// Yearly rule, e.g. "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU"
+	rule string
+
+	// UNTIL of the rule, zero if the rule does not end
+	until time.Time
+
+	// Additional onsets when there is no rule
+	rdates []time.Time
+}
+
+// Generate builds a VTIMEZONE component for the IANA time zone from the
+// time zone database of the system, using time.LoadLocation.
+//
+// Only the observances needed to interpret local times between from and to are included.
+// Transitions that follow a yearly pattern are written as RRULEs, all others as RDATEs.
+func Generate(tzid TZID, from, to time.Time) (string, error) {
+	if to.Before(from) {
+		return "", fmt.Errorf("timezones: range end %s is before start %s", to, from)
+	}
+	loc, err := time.LoadLocation(string(tzid))
+	if err != nil {
+		return "", fmt.Errorf("timezones: failed to load %q: %w", tzid, err)
+	}
+
+	// Look one year past the range, so a rule still in effect at the end is left open
+	transitions := findTransitions(loc, from, to.AddDate(1, 0, 0))
+	observances := buildObservances(transitions, to)
+
+	var builder strings.Builder
+	builder.WriteString("BEGIN:VTIMEZONE" + lineBreak)
+	builder.WriteString("TZID:" + string(tzid) + lineBreak)
+	for _, obs := range observances {
+		obs.write(&builder)
+	}
+	builder.WriteString("END:VTIMEZONE" + lineBreak)
+	return builder.String(), nil
+}
+
+// findTransitions returns the offset in effect at from, as a transition at the start of
+// its period, followed by every change of offset until to.
+func findTransitions(loc *time.Location, from, to time.Time) []transition {
+	current := from.In(loc)
+	name, offset := current.Zone()
+	start, end := current.ZoneBounds()
+
+	// The period containing from, as if it started with a transition
+	first := transition{at: start, offsetFrom: offset, offsetTo: offset, name: name, dst: current.IsDST()}
+	if start.IsZero() {
+		first.at = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Duration(offset) * time.Second)
+	} else {
+		_, first.offsetFrom = start.Add(-time.Second).In(loc).Zone()
+	}
+	transitions := []transition{first}
+
+	for !end.IsZero() && !end.After(to) {
+		next := end.In(loc)
+		nextName, nextOffset := next.Zone()
+		transitions = append(transitions, transition{
+			at:         end,
+			offsetFrom: offset,
+			offsetTo:   nextOffset,
+			name:       nextName,
+			dst:        next.IsDST(),
+		})
+		offset = nextOffset
+		_, end = next.ZoneBounds()
+	}
+	return transitions
+}
+
+// buildObservances groups transitions of the same kind, and compresses yearly
+// patterns into rules. Transitions after the range end only decide whether a rule ends.
+func buildObservances(transitions []transition, rangeEnd time.Time) []observance {
+	groups := make(map[observanceKey][]transition)
+	var keys []observanceKey
+	for _, t := range transitions {
+		key := observanceKey{dst: t.dst, offsetFrom: t.offsetFrom, offsetTo: t.offsetTo, name: t.name}
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	var observances []observance
+	for _, key := range keys {
+		var singles []transition
+		for _, run := range yearlyRuns(groups[key]) {
+			var inRange []transition
+			for _, t := range run.transitions {
+				if !t.at.After(rangeEnd) {
+					inRange = append(inRange, t)
+				}
+			}
+			if len(inRange) == 0 {
+				continue
+			}
+			if run.rule == "" || len(run.transitions) < 2 {
+				singles = append(singles, inRange...)
+				continue
+			}
+
+			obs := observance{key: key, start: inRange[0], rule: run.rule}
+			// A rule that continues past the range end is left open ended
+			if len(inRange) == len(run.transitions) {
+				obs.until = inRange[len(inRange)-1].at.UTC()
+			}
+			observances = append(observances, obs)
+		}
+
+		if len(singles) > 0 {
+			obs := observance{key: key, start: singles[0]}
+			for _, t := range singles[1:] {
+				obs.rdates = append(obs.rdates, t.local())
+			}
+			observances = append(observances, obs)
+		}
+	}
+
+	sort.SliceStable(observances, func(i, j int) bool {
+		return observances[i].start.at.Before(observances[j].start.at)
+	})
+	return observances
+}
+
+// Consecutive transitions following the same yearly rule
+type run struct {
+	rule        string
+	transitions []transition
+}
+
+// yearlyRuns splits transitions into maximal runs, one year apart,
+// on the same weekday of the month, at the same local time.
+func yearlyRuns(transitions []transition) []run {
+	var runs []run
+	for i := 0; i < len(transitions); {
+		current := run{transitions: transitions[i : i+1]}
+		for _, week := range candidateWeeks(transitions[i].local()) {
+			rule := yearlyRule(transitions[i].local(), week)
+			j := i + 1
+			for j < len(transitions) && followsRule(transitions[j-1].local(), transitions[j].local(), week) {
+				j++
+			}
+			if j-i > len(current.transitions) {
+				current = run{rule: rule, transitions: transitions[i:j]}
+			}
+		}
+		runs = append(runs, current)
+		i += len(current.transitions)
+	}
+	return runs
+}
+
+// The weeks of the month t can be described by, preferring -1 when it is in the last week
+func candidateWeeks(t time.Time) []int {
+	week := (t.Day()-1)/7 + 1
+	if t.AddDate(0, 0, 7).Month() == t.Month() {
+		return []int{week}
+	}
+	if week == 5 {
+		return []int{-1}
+	}
+	return []int{-1, week}
+}
+
+func followsRule(prev, next time.Time, week int) bool {
+	if next.Year() != prev.Year()+1 || next.Month() != prev.Month() || next.Weekday() != prev.Weekday() {
+		return false
+	}
+	if next.Hour() != prev.Hour() || next.Minute() != prev.Minute() || next.Second() != prev.Second() {
+		return false
+	}
+	for _, w := range candidateWeeks(next) {
+		if w == week {
+			return true
+		}
+	}
+	return false
+}
+
+func yearlyRule(t time.Time, week int) string {
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(t.Month()), week, weekdayToICal(t.Weekday()))
+}
+
+func (o *observance) write(builder *strings.Builder) {
+	component := "STANDARD"
+	if o.key.dst {
+		component = "DAYLIGHT"
+	}
+
+	builder.WriteString("BEGIN:" + component + lineBreak)
+	builder.WriteString("DTSTART:" + o.start.local().Format(iCalTimeLayout) + lineBreak)
+	if o.rule != "" {
+		rule := "RRULE:" + o.rule
+		if !o.until.IsZero() {
+			rule += ";UNTIL=" + o.until.Format(iCalTimeLayout) + "Z"
+		}
+		builder.WriteString(rule + lineBreak)
+	}
+	if len(o.rdates) > 0 {
+		dates := make([]string, 0, len(o.rdates))
+		for _, d := range o.rdates {
+			dates = append(dates, d.Format(iCalTimeLayout))
+		}
+		builder.WriteString(foldLine("RDATE:"+strings.Join(dates, ",")) + lineBreak)
+	}
+	builder.WriteString("TZNAME:" + o.key.name + lineBreak)
+	builder.WriteString("TZOFFSETFROM:" + formatOffset(o.key.offsetFrom) + lineBreak)
+	builder.WriteString("TZOFFSETTO:" + formatOffset(o.key.offsetTo) + lineBreak)
+	builder.WriteString("END:" + component + lineBreak)
+}
+
+// Formats an offset in seconds east of UTC, e.g. "-0500" or "+053730"
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	offset := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, (seconds/60)%60)
+	if seconds%60 != 0 {
+		offset += fmt.Sprintf("%02d", seconds%60)
+	}
+	return offset
+}
+
+func weekdayToICal(d time.Weekday) string {
+	return strings.ToUpper(d.String()[:2])
+}
+
+// Splits a content line into 75 octet chunks
+func foldLine(line string) string {
+	var builder strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		builder.WriteString(line[:limit] + lineBreak + " ")
+		line = line[limit:]
+		limit = maxLineOctets - 1
+	}
+	builder.WriteString(line)
+	return builder.String()
+}
@@ -0,0 +1,115 @@
+package timezones
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		tzid     TZID
+		contains []string
+		excludes []string
+	}{
+		{
+			America_New_York,
+			[]string{
+				"TZID:America/New_York\r\n",
+				"BEGIN:DAYLIGHT\r\nDTSTART:20240310T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\nTZNAME:EDT\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nEND:DAYLIGHT\r\n",
+				"BEGIN:STANDARD\r\nDTSTART:20231105T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\nTZNAME:EST\r\n",
+			},
+			[]string{"UNTIL=", "RDATE"},
+		},
+		{
+			Europe_London,
+			[]string{"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\n", "RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\n"},
+			nil,
+		},
+		{
+			Asia_Kolkata,
+			[]string{"BEGIN:STANDARD\r\n", "TZOFFSETTO:+0530\r\n"},
+			[]string{"DAYLIGHT", "RRULE"},
+		},
+		{
+			UTC,
+			[]string{"DTSTART:19700101T000000\r\nTZNAME:UTC\r\nTZOFFSETFROM:+0000\r\nTZOFFSETTO:+0000\r\n"},
+			[]string{"DAYLIGHT"},
+		},
+	}
+
+	for _, tt := range tests {
+		data, err := Generate(tt.tzid, from, to)
+		if err != nil {
+			t.Errorf("Generate(%q) returned error: %v", tt.tzid, err)
+			continue
+		}
+		if !strings.HasPrefix(data, "BEGIN:VTIMEZONE\r\n") || !strings.HasSuffix(data, "END:VTIMEZONE\r\n") {
+			t.Errorf("Generate(%q) = %q; want a VTIMEZONE component", tt.tzid, data)
+		}
+		for _, s := range tt.contains {
+			if !strings.Contains(data, s) {
+				t.Errorf("Generate(%q) = %q; want it to contain %q", tt.tzid, data, s)
+			}
+		}
+		for _, s := range tt.excludes {
+			if strings.Contains(data, s) {
+				t.Errorf("Generate(%q) = %q; want it not to contain %q", tt.tzid, data, s)
+			}
+		}
+	}
+}
+
+func TestGenerateTruncatesRules(t *testing.T) {
+	// Moscow stopped observing daylight saving time in 2011
+	from := time.Date(2009, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	data, err := Generate(Europe_Moscow, from, to)
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if !strings.Contains(data, "RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU;UNTIL=20101030T230000Z\r\n") {
+		t.Errorf("Generate() = %q; want the ended rule to have an UNTIL", data)
+	}
+
+	// A range after the rule ended has no daylight observance
+	data, err = Generate(Europe_Moscow, time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), to)
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if strings.Contains(data, "DAYLIGHT") || strings.Count(data, "BEGIN:STANDARD") != 1 {
+		t.Errorf("Generate() = %q; want a single standard observance", data)
+	}
+}
+
+func TestGenerateFailure(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := Generate(TZID("Invalid/Timezone"), from, from.AddDate(1, 0, 0)); err == nil {
+		t.Errorf("Generate() with unknown zone returned no error")
+	}
+	if _, err := Generate(America_New_York, from, from.AddDate(-1, 0, 0)); err == nil {
+		t.Errorf("Generate() with end before start returned no error")
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := []struct {
+		seconds  int
+		expected string
+	}{
+		{0, "+0000"},
+		{-5 * 3600, "-0500"},
+		{5*3600 + 30*60, "+0530"},
+		{-(3*3600 + 30*60), "-0330"},
+		{19800 + 2*60 + 30, "+053230"},
+	}
+
+	for _, tt := range tests {
+		if got := formatOffset(tt.seconds); got != tt.expected {
+			t.Errorf("formatOffset(%d) = %q; want %q", tt.seconds, got, tt.expected)
+		}
+	}
+}
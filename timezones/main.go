@@ -8,21 +8,35 @@ import (
 	"compress/gzip"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 )
 
+//go:generate go run gen_tzid.go
+
 // from `/tz/timezones.json`
 //
 //go:embed timezones.json.gz
 var compressedData []byte
 
-var timezones map[string]string
+// ErrUnknownTimeZone is returned when a source has no definition for a time zone.
+var ErrUnknownTimeZone = errors.New("timezones: unknown time zone")
 
-func init() {
-	err := load()
-	if err != nil {
-		panic(fmt.Sprintf("Failed to load timezones: %v", err))
-	}
+var (
+	// Decoded on first use
+	timezones map[string]string
+	loadOnce  sync.Once
+	loadErr   error
+)
+
+// The embedded definitions, decoded the first time they are needed
+func embeddedZones() (map[string]string, error) {
+	loadOnce.Do(func() {
+		loadErr = load()
+	})
+	return timezones, loadErr
 }
 
 func load() error {
@@ -32,23 +46,49 @@ func load() error {
 	}
 	defer reader.Close()
 
+	var decoded map[string]string
 	decoder := json.NewDecoder(reader)
-	if err := decoder.Decode(&timezones); err != nil {
+	if err := decoder.Decode(&decoded); err != nil {
 		return fmt.Errorf("failed to decode JSON data: %w", err)
 	}
+	timezones = decoded
 	return nil
 }
 
-func Get(tzid TZID) (string, bool) {
-	data, exists := timezones[string(tzid)]
-	if !exists {
-		return "", false
-	}
+// Get returns the VTIMEZONE definition of the time zone from the current source.
+//
+// Returns an error wrapping ErrUnknownTimeZone when the source has no definition,
+// or the error of a source that failed to load.
+func Get(tzid TZID) (string, error) {
+	return currentSource().Lookup(tzid)
+}
 
-	return data, exists
+// Valid reports whether the current source has a definition for the time zone.
+//
+// The error is only set when the source failed to load.
+func (tz *TZID) Valid() (bool, error) {
+	_, err := Get(*tz)
+	if errors.Is(err, ErrUnknownTimeZone) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
-func (tz *TZID) Valid() bool {
-	_, exists := timezones[string(*tz)]
-	return exists
+// Definition returns a VTIMEZONE definition covering the dates between from and to.
+//
+// A source set with SetSource is used first. Otherwise the definition is generated
+// from the time zone database of the system, falling back to the embedded definitions.
+func Definition(tzid TZID, from, to time.Time) (string, error) {
+	if CustomSource() {
+		return Get(tzid)
+	}
+
+	data, err := Generate(tzid, from, to)
+	if err != nil {
+		return Get(tzid)
+	}
+	return data, nil
 }
@@ -1,6 +1,16 @@
 package timezones
 
-import "testing"
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
 
 func TestGet(t *testing.T) {
 	tests := []struct {
@@ -14,9 +24,10 @@ func TestGet(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		data, found := Get(tt.tzid)
+		data, err := Get(tt.tzid)
+		found := err == nil
 		if found != tt.shouldFind {
-			t.Errorf("Get(%q) = found %v; want %v", tt.tzid, found, tt.shouldFind)
+			t.Errorf("Get(%q) = error %v; want found %v", tt.tzid, err, tt.shouldFind)
 		}
 		if found && data == "" {
 			t.Errorf("Get(%q) returned empty data", tt.tzid)
@@ -26,9 +37,9 @@ func TestGet(t *testing.T) {
 
 func TestGetFailure(t *testing.T) {
 	tzid := TZID("NonExistent/Timezone")
-	data, found := Get(tzid)
-	if found {
-		t.Errorf("Get(%q) = found %v; want false", tzid, found)
+	data, err := Get(tzid)
+	if !errors.Is(err, ErrUnknownTimeZone) {
+		t.Errorf("Get(%q) error = %v; want %v", tzid, err, ErrUnknownTimeZone)
 	}
 	if data != "" {
 		t.Errorf("Get(%q) returned data %q; want empty string", tzid, data)
@@ -46,8 +57,12 @@ func TestValid(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		if tt.tzid.Valid() != tt.isValid {
-			t.Errorf("Valid(%q) = %v; want %v", tt.tzid, tt.tzid.Valid(), tt.isValid)
+		valid, err := tt.tzid.Valid()
+		if err != nil {
+			t.Errorf("Valid(%q) returned error: %v", tt.tzid, err)
+		}
+		if valid != tt.isValid {
+			t.Errorf("Valid(%q) = %v; want %v", tt.tzid, valid, tt.isValid)
 		}
 	}
 }
@@ -61,3 +76,95 @@ func TestLoad(t *testing.T) {
 		t.Errorf("load() did not populate timezones map")
 	}
 }
+
+func TestEmbeddedZonesLoadOnce(t *testing.T) {
+	first, err := embeddedZones()
+	if err != nil {
+		t.Fatalf("embeddedZones() returned error: %v", err)
+	}
+	second, _ := embeddedZones()
+	if len(first) == 0 || len(first) != len(second) {
+		t.Errorf("embeddedZones() = %d zones, then %d; want the same non-empty table", len(first), len(second))
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	data, err := Definition(America_New_York, from, to)
+	if err != nil || strings.Contains(data, "COMMENT:") {
+		t.Errorf("Definition() = %q, %v; want a generated definition", data, err)
+	}
+
+	SetSource(FromFS(fstest.MapFS{
+		"America/New_York.ics": {Data: []byte("BEGIN:VTIMEZONE\nTZID:America/New_York\nX-SOURCE:custom\nEND:VTIMEZONE\n")},
+	}))
+	t.Cleanup(func() { SetSource(nil) })
+
+	data, err = Definition(America_New_York, from, to)
+	if err != nil || !strings.Contains(data, "X-SOURCE:custom\r\n") {
+		t.Errorf("Definition() = %q, %v; want the definition of the custom source", data, err)
+	}
+	if _, err := Definition(Europe_Paris, from, to); !errors.Is(err, ErrUnknownTimeZone) {
+		t.Errorf("Definition() error = %v; want %v", err, ErrUnknownTimeZone)
+	}
+}
+
+func TestConstantsResolve(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "tzid.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse tzid.go: %v", err)
+	}
+
+	count := 0
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			lit, ok := spec.Values[i].(*ast.BasicLit)
+			if !ok {
+				t.Errorf("%s is not a string literal", name.Name)
+				continue
+			}
+			value, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Errorf("%s has an invalid value %s", name.Name, lit.Value)
+				continue
+			}
+			if _, err := Get(TZID(value)); err != nil {
+				t.Errorf("Get(%s) = not found; %q is not an embedded time zone", name.Name, value)
+			}
+			count++
+		}
+		return false
+	})
+
+	if count != len(timezones) {
+		t.Errorf("tzid.go has %d constants; want one for each of the %d embedded time zones", count, len(timezones))
+	}
+}
+
+func TestConstantValues(t *testing.T) {
+	tests := []struct {
+		tzid     TZID
+		expected string
+	}{
+		{Africa_Abidjan, "Africa/Abidjan"},
+		{America_Argentina_Buenos_Aires, "America/Argentina/Buenos_Aires"},
+		{Etc_GMT_add_5, "Etc/GMT+5"},
+		{Etc_GMT_subtract_5, "Etc/GMT-5"},
+		{America_Port_au_Prince, "America/Port-au-Prince"},
+	}
+
+	for _, tt := range tests {
+		if string(tt.tzid) != tt.expected {
+			t.Errorf("constant = %q; want %q", tt.tzid, tt.expected)
+		}
+		if valid, _ := tt.tzid.Valid(); !valid {
+			t.Errorf("Valid(%q) = false; want true", tt.tzid)
+		}
+	}
+}
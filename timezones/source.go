@@ -0,0 +1,127 @@
+package timezones
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Source provides VTIMEZONE definitions
+type Source interface {
+	// Lookup returns the VTIMEZONE component of the time zone, with CRLF line breaks.
+	//
+	// Returns an error wrapping ErrUnknownTimeZone when there is no definition.
+	Lookup(tzid TZID) (string, error)
+}
+
+var (
+	sourceMu sync.RWMutex
+	source   Source
+)
+
+// SetSource replaces the source used by Get, Valid and Definition.
+//
+// A nil source restores the embedded definitions.
+func SetSource(src Source) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	source = src
+}
+
+func currentSource() Source {
+	sourceMu.RLock()
+	defer sourceMu.RUnlock()
+	if source == nil {
+		return Embedded()
+	}
+	return source
+}
+
+// CustomSource reports whether SetSource replaced the embedded definitions
+func CustomSource() bool {
+	sourceMu.RLock()
+	defer sourceMu.RUnlock()
+	return source != nil
+}
+
+// Embedded returns the source of the definitions embedded in this package,
+// copied from https://github.com/Tylerchristensen100/iCal_VTIMEZONE
+func Embedded() Source {
+	return embeddedSource{}
+}
+
+type embeddedSource struct{}
+
+func (embeddedSource) Lookup(tzid TZID) (string, error) {
+	zones, err := embeddedZones()
+	if err != nil {
+		return "", fmt.Errorf("timezones: failed to load embedded time zones: %w", err)
+	}
+	data, exists := zones[string(tzid)]
+	if !exists {
+		return "", fmt.Errorf("%w: %q", ErrUnknownTimeZone, tzid)
+	}
+	return data, nil
+}
+
+// FromFS returns a source reading one file per time zone, named after the TZID
+// with an ".ics" extension, e.g. "America/New_York.ics".
+//
+// The file may hold a bare VTIMEZONE component or a VCALENDAR containing one.
+func FromFS(fsys fs.FS) Source {
+	return fsSource{fsys: fsys}
+}
+
+// FromDir returns a source reading the files of FromFS from a directory.
+func FromDir(dir string) Source {
+	return FromFS(os.DirFS(dir))
+}
+
+type fsSource struct {
+	fsys fs.FS
+}
+
+func (s fsSource) Lookup(tzid TZID) (string, error) {
+	name := string(tzid) + ".ics"
+	if !fs.ValidPath(name) || path.Clean(name) != name {
+		return "", fmt.Errorf("%w: %q", ErrUnknownTimeZone, tzid)
+	}
+
+	data, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %q", ErrUnknownTimeZone, tzid)
+		}
+		return "", fmt.Errorf("timezones: failed to read %q: %w", name, err)
+	}
+
+	component, found := extractVTimeZone(string(data))
+	if !found {
+		return "", fmt.Errorf("timezones: %q has no VTIMEZONE component", name)
+	}
+	return component, nil
+}
+
+// Extracts the first VTIMEZONE component, normalizing line breaks to CRLF
+func extractVTimeZone(data string) (string, bool) {
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+
+	var builder strings.Builder
+	inside := false
+	for _, line := range lines {
+		if strings.EqualFold(line, "BEGIN:VTIMEZONE") {
+			inside = true
+		}
+		if !inside {
+			continue
+		}
+		builder.WriteString(line + lineBreak)
+		if strings.EqualFold(line, "END:VTIMEZONE") {
+			return builder.String(), true
+		}
+	}
+	return "", false
+}
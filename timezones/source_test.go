@@ -0,0 +1,89 @@
+package timezones
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Europe/Paris.ics": {Data: []byte("BEGIN:VTIMEZONE\r\nTZID:Europe/Paris\r\nEND:VTIMEZONE\r\n")},
+		"America/Chicago.ics": {Data: []byte(
+			"BEGIN:VCALENDAR\nVERSION:2.0\nBEGIN:VTIMEZONE\nTZID:America/Chicago\nEND:VTIMEZONE\nEND:VCALENDAR\n")},
+		"Broken/Zone.ics": {Data: []byte("BEGIN:VCALENDAR\nEND:VCALENDAR\n")},
+	}
+	source := FromFS(fsys)
+
+	tests := []struct {
+		tzid     TZID
+		expected string
+	}{
+		{Europe_Paris, "BEGIN:VTIMEZONE\r\nTZID:Europe/Paris\r\nEND:VTIMEZONE\r\n"},
+		{America_Chicago, "BEGIN:VTIMEZONE\r\nTZID:America/Chicago\r\nEND:VTIMEZONE\r\n"},
+	}
+	for _, tt := range tests {
+		data, err := source.Lookup(tt.tzid)
+		if err != nil || data != tt.expected {
+			t.Errorf("Lookup(%q) = %q, %v; want %q", tt.tzid, data, err, tt.expected)
+		}
+	}
+
+	for _, tzid := range []TZID{America_New_York, "../Europe/Paris"} {
+		if _, err := source.Lookup(tzid); !errors.Is(err, ErrUnknownTimeZone) {
+			t.Errorf("Lookup(%q) error = %v; want %v", tzid, err, ErrUnknownTimeZone)
+		}
+	}
+	if _, err := source.Lookup("Broken/Zone"); err == nil || errors.Is(err, ErrUnknownTimeZone) {
+		t.Errorf("Lookup(%q) error = %v; want a malformed file error", "Broken/Zone", err)
+	}
+}
+
+func TestFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "Asia"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	definition := "BEGIN:VTIMEZONE\r\nTZID:Asia/Tokyo\r\nEND:VTIMEZONE\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "Asia", "Tokyo.ics"), []byte(definition), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetSource(FromDir(dir))
+	t.Cleanup(func() { SetSource(nil) })
+
+	data, err := Get(Asia_Tokyo)
+	if err != nil || data != definition {
+		t.Errorf("Get(%q) = %q, %v; want %q", Asia_Tokyo, data, err, definition)
+	}
+	tzid := America_New_York
+	if valid, err := tzid.Valid(); valid || err != nil {
+		t.Errorf("Valid(%q) = %v, %v; want false, nil", tzid, valid, err)
+	}
+
+	SetSource(nil)
+	data, err = Get(America_New_York)
+	if err != nil || !strings.Contains(data, "TZID:America/New_York") {
+		t.Errorf("Get(%q) after reset = %q, %v; want the embedded definition", America_New_York, data, err)
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) Lookup(TZID) (string, error) {
+	return "", errors.New("source unavailable")
+}
+
+func TestValidSourceFailure(t *testing.T) {
+	SetSource(failingSource{})
+	t.Cleanup(func() { SetSource(nil) })
+
+	tzid := America_New_York
+	valid, err := tzid.Valid()
+	if valid || err == nil {
+		t.Errorf("Valid(%q) = %v, %v; want false with the source error", tzid, valid, err)
+	}
+}
@@ -1,12 +1,14 @@
+// Code generated by gen_tzid.go; DO NOT EDIT.
+
 package timezones
 
 // iCalendar VTIMEZONE component
 type TZID string
 
 const (
-	Africa_Abidjan                   TZID = "Africa_Abidjan"
-	Africa_Accra                     TZID = "Africa_Accra"
-	Africa_Addis_Ababa               TZID = "Africa_Addis_Ababa"
+	Africa_Abidjan                   TZID = "Africa/Abidjan"
+	Africa_Accra                     TZID = "Africa/Accra"
+	Africa_Addis_Ababa               TZID = "Africa/Addis_Ababa"
 	Africa_Algiers                   TZID = "Africa/Algiers"
 	Africa_Asmara                    TZID = "Africa/Asmara"
 	Africa_Asmera                    TZID = "Africa/Asmera"
@@ -63,8 +65,8 @@ const (
 	America_Anguilla                 TZID = "America/Anguilla"
 	America_Antigua                  TZID = "America/Antigua"
 	America_Araguaina                TZID = "America/Araguaina"
-	America_Argentina_Buenos_Aires   TZID = "America_Argentina_Buenos_Aires"
-	America_Argentina_Catamarca      TZID = "America_Argentina_Catamarca"
+	America_Argentina_Buenos_Aires   TZID = "America/Argentina/Buenos_Aires"
+	America_Argentina_Catamarca      TZID = "America/Argentina/Catamarca"
 	America_Argentina_ComodRivadavia TZID = "America/Argentina/ComodRivadavia"
 	America_Argentina_Cordoba        TZID = "America/Argentina/Cordoba"
 	America_Argentina_Jujuy          TZID = "America/Argentina/Jujuy"
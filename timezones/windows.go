@@ -0,0 +1,390 @@
+package timezones
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Windows time zone names, as used by Outlook and Exchange, mapped to the IANA time zone
+// of their "001" (default) territory.
+//
+// From the CLDR windowsZones table:
+// https://github.com/unicode-org/cldr/blob/main/common/supplemental/windowsZones.xml
+var windowsZones = map[string]TZID{
+	"Dateline Standard Time":          Etc_GMT_add_12,
+	"UTC-11":                          Etc_GMT_add_11,
+	"Aleutian Standard Time":          America_Adak,
+	"Hawaiian Standard Time":          Pacific_Honolulu,
+	"Marquesas Standard Time":         Pacific_Marquesas,
+	"Alaskan Standard Time":           America_Anchorage,
+	"UTC-09":                          Etc_GMT_add_9,
+	"Pacific Standard Time (Mexico)":  America_Tijuana,
+	"UTC-08":                          Etc_GMT_add_8,
+	"Pacific Standard Time":           America_Los_Angeles,
+	"US Mountain Standard Time":       America_Phoenix,
+	"Mountain Standard Time (Mexico)": America_Mazatlan,
+	"Mountain Standard Time":          America_Denver,
+	"Yukon Standard Time":             America_Whitehorse,
+	"Central America Standard Time":   America_Guatemala,
+	"Central Standard Time":           America_Chicago,
+	"Easter Island Standard Time":     Pacific_Easter,
+	"Central Standard Time (Mexico)":  America_Mexico_City,
+	"Canada Central Standard Time":    America_Regina,
+	"SA Pacific Standard Time":        America_Bogota,
+	"Eastern Standard Time (Mexico)":  America_Cancun,
+	"Eastern Standard Time":           America_New_York,
+	"Haiti Standard Time":             America_Port_au_Prince,
+	"Cuba Standard Time":              America_Havana,
+	"US Eastern Standard Time":        America_Indiana_Indianapolis,
+	"Turks And Caicos Standard Time":  America_Grand_Turk,
+	"Paraguay Standard Time":          America_Asuncion,
+	"Atlantic Standard Time":          America_Halifax,
+	"Venezuela Standard Time":         America_Caracas,
+	"Central Brazilian Standard Time": America_Cuiaba,
+	"SA Western Standard Time":        America_La_Paz,
+	"Pacific SA Standard Time":        America_Santiago,
+	"Newfoundland Standard Time":      America_St_Johns,
+	"Tocantins Standard Time":         America_Araguaina,
+	"E. South America Standard Time":  America_Sao_Paulo,
+	"SA Eastern Standard Time":        America_Cayenne,
+	"Argentina Standard Time":         America_Argentina_Buenos_Aires,
+	"Greenland Standard Time":         America_Nuuk,
+	"Montevideo Standard Time":        America_Montevideo,
+	"Magallanes Standard Time":        America_Punta_Arenas,
+	"Saint Pierre Standard Time":      America_Miquelon,
+	"Bahia Standard Time":             America_Bahia,
+	"UTC-02":                          Etc_GMT_add_2,
+	"Azores Standard Time":            Atlantic_Azores,
+	"Cape Verde Standard Time":        Atlantic_Cape_Verde,
+	"UTC":                             Etc_UTC,
+	"GMT Standard Time":               Europe_London,
+	"Greenwich Standard Time":         Atlantic_Reykjavik,
+	"Sao Tome Standard Time":          Africa_Sao_Tome,
+	"Morocco Standard Time":           Africa_Casablanca,
+	"W. Europe Standard Time":         Europe_Berlin,
+	"Central Europe Standard Time":    Europe_Budapest,
+	"Romance Standard Time":           Europe_Paris,
+	"Central European Standard Time":  Europe_Warsaw,
+	"W. Central Africa Standard Time": Africa_Lagos,
+	"Jordan Standard Time":            Asia_Amman,
+	"GTB Standard Time":               Europe_Bucharest,
+	"Middle East Standard Time":       Asia_Beirut,
+	"Egypt Standard Time":             Africa_Cairo,
+	"E. Europe Standard Time":         Europe_Chisinau,
+	"Syria Standard Time":             Asia_Damascus,
+	"West Bank Standard Time":         Asia_Hebron,
+	"South Africa Standard Time":      Africa_Johannesburg,
+	"FLE Standard Time":               Europe_Kyiv,
+	"Israel Standard Time":            Asia_Jerusalem,
+	"South Sudan Standard Time":       Africa_Juba,
+	"Kaliningrad Standard Time":       Europe_Kaliningrad,
+	"Sudan Standard Time":             Africa_Khartoum,
+	"Libya Standard Time":             Africa_Tripoli,
+	"Namibia Standard Time":           Africa_Windhoek,
+	"Arabic Standard Time":            Asia_Baghdad,
+	"Turkey Standard Time":            Europe_Istanbul,
+	"Arab Standard Time":              Asia_Riyadh,
+	"Belarus Standard Time":           Europe_Minsk,
+	"Russian Standard Time":           Europe_Moscow,
+	"E. Africa Standard Time":         Africa_Nairobi,
+	"Volgograd Standard Time":         Europe_Volgograd,
+	"Iran Standard Time":              Asia_Tehran,
+	"Arabian Standard Time":           Asia_Dubai,
+	"Astrakhan Standard Time":         Europe_Astrakhan,
+	"Azerbaijan Standard Time":        Asia_Baku,
+	"Russia Time Zone 3":              Europe_Samara,
+	"Mauritius Standard Time":         Indian_Mauritius,
+	"Saratov Standard Time":           Europe_Saratov,
+	"Georgian Standard Time":          Asia_Tbilisi,
+	"Caucasus Standard Time":          Asia_Yerevan,
+	"Afghanistan Standard Time":       Asia_Kabul,
+	"West Asia Standard Time":         Asia_Tashkent,
+	"Qyzylorda Standard Time":         Asia_Qyzylorda,
+	"Ekaterinburg Standard Time":      Asia_Yekaterinburg,
+	"Pakistan Standard Time":          Asia_Karachi,
+	"India Standard Time":             Asia_Kolkata,
+	"Sri Lanka Standard Time":         Asia_Colombo,
+	"Nepal Standard Time":             Asia_Kathmandu,
+	"Central Asia Standard Time":      Asia_Almaty,
+	"Bangladesh Standard Time":        Asia_Dhaka,
+	"Omsk Standard Time":              Asia_Omsk,
+	"Myanmar Standard Time":           Asia_Yangon,
+	"SE Asia Standard Time":           Asia_Bangkok,
+	"Altai Standard Time":             Asia_Barnaul,
+	"W. Mongolia Standard Time":       Asia_Hovd,
+	"North Asia Standard Time":        Asia_Krasnoyarsk,
+	"N. Central Asia Standard Time":   Asia_Novosibirsk,
+	"Tomsk Standard Time":             Asia_Tomsk,
+	"China Standard Time":             Asia_Shanghai,
+	"North Asia East Standard Time":   Asia_Irkutsk,
+	"Singapore Standard Time":         Asia_Singapore,
+	"W. Australia Standard Time":      Australia_Perth,
+	"Taipei Standard Time":            Asia_Taipei,
+	"Ulaanbaatar Standard Time":       Asia_Ulaanbaatar,
+	"Aus Central W. Standard Time":    Australia_Eucla,
+	"Transbaikal Standard Time":       Asia_Chita,
+	"Tokyo Standard Time":             Asia_Tokyo,
+	"North Korea Standard Time":       Asia_Pyongyang,
+	"Korea Standard Time":             Asia_Seoul,
+	"Yakutsk Standard Time":           Asia_Yakutsk,
+	"Cen. Australia Standard Time":    Australia_Adelaide,
+	"AUS Central Standard Time":       Australia_Darwin,
+	"E. Australia Standard Time":      Australia_Brisbane,
+	"AUS Eastern Standard Time":       Australia_Sydney,
+	"West Pacific Standard Time":      Pacific_Port_Moresby,
+	"Tasmania Standard Time":          Australia_Hobart,
+	"Vladivostok Standard Time":       Asia_Vladivostok,
+	"Lord Howe Standard Time":         Australia_Lord_Howe,
+	"Bougainville Standard Time":      Pacific_Bougainville,
+	"Russia Time Zone 10":             Asia_Srednekolymsk,
+	"Magadan Standard Time":           Asia_Magadan,
+	"Norfolk Standard Time":           Pacific_Norfolk,
+	"Sakhalin Standard Time":          Asia_Sakhalin,
+	"Central Pacific Standard Time":   Pacific_Guadalcanal,
+	"Russia Time Zone 11":             Asia_Kamchatka,
+	"New Zealand Standard Time":       Pacific_Auckland,
+	"UTC+12":                          Etc_GMT_subtract_12,
+	"Fiji Standard Time":              Pacific_Fiji,
+	"Chatham Islands Standard Time":   Pacific_Chatham,
+	"UTC+13":                          Etc_GMT_subtract_13,
+	"Tonga Standard Time":             Pacific_Tongatapu,
+	"Samoa Standard Time":             Pacific_Apia,
+	"Line Islands Standard Time":      Pacific_Kiritimati,
+}
+
+// IANA time zones of other territories, and aliases, that share a Windows time zone
+var windowsAliases = map[TZID]string{
+	UTC:                 "UTC",
+	Etc_GMT:             "UTC",
+	GMT:                 "UTC",
+	Zulu:                "UTC",
+	US_Eastern:          "Eastern Standard Time",
+	US_Central:          "Central Standard Time",
+	US_Mountain:         "Mountain Standard Time",
+	US_Pacific:          "Pacific Standard Time",
+	US_Alaska:           "Alaskan Standard Time",
+	US_Hawaii:           "Hawaiian Standard Time",
+	US_Arizona:          "US Mountain Standard Time",
+	America_Detroit:     "Eastern Standard Time",
+	America_Toronto:     "Eastern Standard Time",
+	America_Winnipeg:    "Central Standard Time",
+	America_Edmonton:    "Mountain Standard Time",
+	America_Boise:       "Mountain Standard Time",
+	America_Vancouver:   "Pacific Standard Time",
+	Asia_Calcutta:       "India Standard Time",
+	Asia_Hong_Kong:      "China Standard Time",
+	Asia_Katmandu:       "Nepal Standard Time",
+	Asia_Rangoon:        "Myanmar Standard Time",
+	Australia_Melbourne: "AUS Eastern Standard Time",
+	Australia_Canberra:  "AUS Eastern Standard Time",
+	Europe_Kiev:         "FLE Standard Time",
+	Europe_Helsinki:     "FLE Standard Time",
+	Europe_Riga:         "FLE Standard Time",
+	Europe_Sofia:        "FLE Standard Time",
+	Europe_Tallinn:      "FLE Standard Time",
+	Europe_Vilnius:      "FLE Standard Time",
+	Europe_Athens:       "GTB Standard Time",
+	Europe_Dublin:       "GMT Standard Time",
+	Europe_Lisbon:       "GMT Standard Time",
+	Atlantic_Canary:     "GMT Standard Time",
+	Europe_Amsterdam:    "W. Europe Standard Time",
+	Europe_Rome:         "W. Europe Standard Time",
+	Europe_Stockholm:    "W. Europe Standard Time",
+	Europe_Vienna:       "W. Europe Standard Time",
+	Europe_Zurich:       "W. Europe Standard Time",
+	Europe_Oslo:         "W. Europe Standard Time",
+	Europe_Luxembourg:   "W. Europe Standard Time",
+	Europe_Brussels:     "Romance Standard Time",
+	Europe_Copenhagen:   "Romance Standard Time",
+	Europe_Madrid:       "Romance Standard Time",
+	Europe_Prague:       "Central Europe Standard Time",
+	Europe_Belgrade:     "Central Europe Standard Time",
+	Europe_Bratislava:   "Central Europe Standard Time",
+	Europe_Ljubljana:    "Central Europe Standard Time",
+	Europe_Sarajevo:     "Central European Standard Time",
+	Europe_Skopje:       "Central European Standard Time",
+	Europe_Zagreb:       "Central European Standard Time",
+	America_Godthab:     "Greenland Standard Time",
+	America_Nuuk:        "Greenland Standard Time",
+}
+
+// Display names of Windows time zones that do not name a zone or city
+var windowsDisplayNames = map[string]TZID{
+	"coordinated universal time":   UTC,
+	"greenwich mean time":          Etc_GMT,
+	"international date line west": Etc_GMT_add_12,
+}
+
+// E.g., "(UTC-05:00) Eastern Time (US & Canada)" or "(GMT+01:00) Amsterdam, Berlin"
+var displayNamePattern = regexp.MustCompile(`^\((?:UTC|GMT)(?:([+-])(\d{1,2}):(\d{2}))?\)\s*(.*)$`)
+
+var parenthesesPattern = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// Resolve normalizes a time zone name found in the wild to an IANA time zone.
+//
+// Accepted forms:
+//   - IANA names, ignoring case, e.g. "America/New_York"
+//   - Windows names, e.g. "Eastern Standard Time"
+//   - Windows display names, e.g. "(UTC-05:00) Eastern Time (US & Canada)"
+//   - Prefixed IANA names, e.g. "/mozilla.org/20050126_1/America/New_York"
+//
+// Returns an error wrapping ErrUnknownTimeZone when the name can not be resolved,
+// or the error of a source that failed to load.
+func Resolve(name string) (TZID, error) {
+	name = strings.Trim(strings.TrimSpace(name), `"`)
+	if name == "" {
+		return "", fmt.Errorf("%w: %q", ErrUnknownTimeZone, name)
+	}
+
+	if tzid, ok, err := resolveIANA(name); ok || err != nil {
+		return tzid, err
+	}
+	if tzid, ok := resolveWindows(name); ok {
+		return tzid, nil
+	}
+
+	// Prefixed names, try every trailing part of the path
+	if strings.Contains(name, "/") {
+		parts := strings.Split(strings.Trim(name, "/"), "/")
+		for i := 1; i < len(parts); i++ {
+			if tzid, ok, err := resolveIANA(strings.Join(parts[i:], "/")); ok || err != nil {
+				return tzid, err
+			}
+		}
+	}
+
+	if match := displayNamePattern.FindStringSubmatch(name); match != nil {
+		if tzid, ok, err := resolveDisplayName(match); ok || err != nil {
+			return tzid, err
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownTimeZone, name)
+}
+
+// WindowsName returns the Windows time zone name of an IANA time zone, e.g. "Eastern Standard Time".
+func WindowsName(tzid TZID) (string, bool) {
+	if name, ok := windowsAliases[tzid]; ok {
+		return name, true
+	}
+	for name, zone := range windowsZones {
+		if zone == tzid {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func resolveIANA(name string) (TZID, bool, error) {
+	tzid := TZID(name)
+	valid, err := tzid.Valid()
+	if err != nil {
+		return "", false, err
+	}
+	if valid {
+		return tzid, true, nil
+	}
+	ids, err := sortedIDs()
+	if err != nil {
+		return "", false, err
+	}
+	for _, id := range ids {
+		if strings.EqualFold(id, name) {
+			return TZID(id), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func resolveWindows(name string) (TZID, bool) {
+	if tzid, ok := windowsZones[name]; ok {
+		return tzid, true
+	}
+	for windowsName, tzid := range windowsZones {
+		if strings.EqualFold(windowsName, name) {
+			return tzid, true
+		}
+	}
+	return "", false
+}
+
+// Resolves the parts of a display name, matched by displayNamePattern
+func resolveDisplayName(match []string) (TZID, bool, error) {
+	offset := 0
+	if match[1] != "" {
+		hours := int(match[2][0] - '0')
+		if len(match[2]) == 2 {
+			hours = hours*10 + int(match[2][1]-'0')
+		}
+		minutes := int(match[3][0]-'0')*10 + int(match[3][1]-'0')
+		offset = (hours*60 + minutes) * 60
+		if match[1] == "-" {
+			offset = -offset
+		}
+	}
+	description := strings.TrimSpace(match[4])
+
+	if tzid, ok := windowsDisplayNames[strings.ToLower(description)]; ok {
+		return tzid, true, nil
+	}
+
+	// "Eastern Time (US & Canada)" is the display name of "Eastern Standard Time"
+	general := strings.TrimSpace(parenthesesPattern.ReplaceAllString(description, ""))
+	general = strings.TrimSuffix(general, " Time")
+	for _, candidate := range []string{description, general + " Standard Time"} {
+		if tzid, ok := resolveWindows(candidate); ok && hasStandardOffset(tzid, offset) {
+			return tzid, true, nil
+		}
+	}
+
+	// "Amsterdam, Berlin, Bern, Rome, Stockholm, Vienna" lists cities of the zone
+	ids, err := sortedIDs()
+	if err != nil {
+		return "", false, err
+	}
+	for _, city := range strings.Split(general, ",") {
+		city = strings.ReplaceAll(strings.TrimSpace(city), " ", "_")
+		if city == "" {
+			continue
+		}
+		for _, id := range ids {
+			last := id[strings.LastIndexByte(id, '/')+1:]
+			if strings.EqualFold(last, city) && hasStandardOffset(TZID(id), offset) {
+				return TZID(id), true, nil
+			}
+		}
+	}
+	return "", false, nil
+}
+
+// Whether the zone currently has the UTC offset in seconds, in winter or summer.
+// Zones that can not be loaded are assumed to match.
+func hasStandardOffset(tzid TZID, offset int) bool {
+	loc, err := time.LoadLocation(string(tzid))
+	if err != nil {
+		return true
+	}
+	year := time.Now().Year()
+	for _, month := range []time.Month{time.January, time.July} {
+		if _, zoneOffset := time.Date(year, month, 1, 0, 0, 0, 0, loc).Zone(); zoneOffset == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// The embedded time zone identifiers, sorted so lookups are deterministic
+func sortedIDs() ([]string, error) {
+	zones, err := embeddedZones()
+	if err != nil {
+		return nil, fmt.Errorf("timezones: failed to load embedded time zones: %w", err)
+	}
+	ids := make([]string, 0, len(zones))
+	for id := range zones {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
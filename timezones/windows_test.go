@@ -0,0 +1,90 @@
+package timezones
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected TZID
+		found    bool
+	}{
+		{"America/New_York", America_New_York, true},
+		{"america/new_york", America_New_York, true},
+		{`"Europe/Berlin"`, Europe_Berlin, true},
+		{"Eastern Standard Time", America_New_York, true},
+		{"w. europe standard time", Europe_Berlin, true},
+		{"(UTC-05:00) Eastern Time (US & Canada)", America_New_York, true},
+		{"(UTC-08:00) Pacific Time (US & Canada)", America_Los_Angeles, true},
+		{"(UTC+01:00) Amsterdam, Berlin, Bern, Rome, Stockholm, Vienna", Europe_Amsterdam, true},
+		{"(UTC+09:00) Osaka, Sapporo, Tokyo", Asia_Tokyo, true},
+		{"(UTC) Dublin, Edinburgh, Lisbon, London", Europe_Dublin, true},
+		{"(UTC) Coordinated Universal Time", UTC, true},
+		{"(GMT-06:00) Central Time (US & Canada)", America_Chicago, true},
+		{"/mozilla.org/20050126_1/America/New_York", America_New_York, true},
+		{"/softwarestudio.org/Olson_20011030_5/America/Argentina/Buenos_Aires", America_Argentina_Buenos_Aires, true},
+		{"/citadel.org/20190914_1/Europe/London", Europe_London, true},
+		// Berlin does not match the offset of the display name
+		{"(UTC+05:00) Berlin", "", false},
+		{"", "", false},
+		{"Not A Time Zone", "", false},
+	}
+
+	for _, tt := range tests {
+		tzid, err := Resolve(tt.name)
+		if found := err == nil; found != tt.found || tzid != tt.expected {
+			t.Errorf("Resolve(%q) = %q, %v; want %q, found %v", tt.name, tzid, err, tt.expected, tt.found)
+		}
+	}
+}
+
+func TestWindowsName(t *testing.T) {
+	tests := []struct {
+		tzid     TZID
+		expected string
+		found    bool
+	}{
+		{America_New_York, "Eastern Standard Time", true},
+		{US_Eastern, "Eastern Standard Time", true},
+		{Europe_Berlin, "W. Europe Standard Time", true},
+		{Europe_Amsterdam, "W. Europe Standard Time", true},
+		{UTC, "UTC", true},
+		{Antarctica_Troll, "", false},
+	}
+
+	for _, tt := range tests {
+		name, found := WindowsName(tt.tzid)
+		if found != tt.found || name != tt.expected {
+			t.Errorf("WindowsName(%q) = %q, %v; want %q, %v", tt.tzid, name, found, tt.expected, tt.found)
+		}
+	}
+}
+
+func TestWindowsZones(t *testing.T) {
+	seen := make(map[TZID]string)
+	for name, tzid := range windowsZones {
+		if valid, _ := tzid.Valid(); !valid {
+			t.Errorf("%q maps to unknown time zone %q", name, tzid)
+		}
+		if other, exists := seen[tzid]; exists {
+			t.Errorf("%q and %q both map to %q", name, other, tzid)
+		}
+		seen[tzid] = name
+	}
+	for tzid, name := range windowsAliases {
+		if _, exists := windowsZones[name]; !exists {
+			t.Errorf("alias %q refers to unknown Windows time zone %q", tzid, name)
+		}
+	}
+}
+
+func TestResolveSourceFailure(t *testing.T) {
+	SetSource(failingSource{})
+	t.Cleanup(func() { SetSource(nil) })
+
+	if tzid, err := Resolve("America/New_York"); err == nil || errors.Is(err, ErrUnknownTimeZone) {
+		t.Errorf("Resolve() = %q, %v; want the source error", tzid, err)
+	}
+}
@@ -1,7 +1,6 @@
 package ical
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,6 +10,19 @@ import (
 // https://icalendar.org/iCalendar-RFC-5545/3-6-2-to-do-component.html
 type Todo struct {
 
+	// OPTIONAL: Persistent, globally unique identifier of the To-Do
+	//
+	// Generated from the summary if empty
+	UID string
+
+	// OPTIONAL: Revision of the To-Do, increased by the organizer on every significant change
+	//
+	// Written as SEQUENCE when it is not 0
+	Sequence int
+
+	// OPTIONAL: When the To-Do was last changed, written as LAST-MODIFIED in UTC
+	LastModified *time.Time
+
 	// REQUIRED: Short summary or title of the To-Do
 	Summary string
 
@@ -37,6 +49,11 @@ type Todo struct {
 	// OPTIONAL: Start date/time of the To-Do.
 	StartDate *time.Time
 
+	// OPTIONAL: Time zone of the start and due date/times
+	//
+	// Written as floating local times, without a time zone, when empty
+	TimeZone TimeZone
+
 	// OPTIONAL: Reminders
 	Reminders []Reminder
 
@@ -44,25 +61,49 @@ type Todo struct {
 	Organizer Participant
 
 	// OPTIONAL: Recurrence rules for the To-Do
+	//
+	// The first instance starts at Recurrence.StartTime, on the start date (or the due date when
+	// there is no start date). Weekly To-Dos start on the next Recurrence.Day instead.
+	// Each instance is due at Recurrence.EndTime, or after Recurrence.Duration.
 	Recurrence *Recurrences
+
+	// OPTIONAL: Documents associated with the To-Do
+	Attachments []Attachment
+
+	// OPTIONAL: Relationships to other components, by UID
+	Relations []Relation
+
+	// What the To-Do was read from by Parse, nil for To-Dos created in code
+	source *source
 }
 
-func (t *Todo) generate(builder *strings.Builder) error {
-	if !t.valid() {
-		return errors.New("Invalid Todo component")
+func (t *Todo) generate(builder *strings.Builder, opts generateOptions) error {
+	if err := t.Validate(); err != nil {
+		return err
 	}
+	start, due := t.StartDate, t.Due
+	if t.Recurrence != nil {
+		instanceStart, err := t.firstInstance()
+		if err != nil {
+			return err
+		}
+		instanceDue := t.Recurrence.occurrenceEnd(instanceStart)
+		start, due = &instanceStart, &instanceDue
+	}
+
 	builder.WriteString("BEGIN:VTODO" + lineBreak)
 	builder.WriteString("UID:" + t.uid() + lineBreak)
-	builder.WriteString("DTSTAMP:" + timeToICal(time.Now().UTC()) + lineBreak)
-	builder.WriteString("SUMMARY:" + t.Summary + lineBreak)
+	builder.WriteString("DTSTAMP:" + timeToICal(time.Now().UTC()) + "Z" + lineBreak)
+	generateRevision(builder, t.Sequence, t.LastModified)
+	builder.WriteString(foldLine("SUMMARY:"+escapeText(t.Summary)) + lineBreak)
 	if t.Status != "" {
 		builder.WriteString("STATUS:" + string(t.Status) + lineBreak)
 	}
-	if t.Due != nil {
-		builder.WriteString("DUE:" + timeToICal(*t.Due) + lineBreak)
+	if due != nil {
+		builder.WriteString(zonedTime("DUE", *due, t.TimeZone, opts) + lineBreak)
 	}
 	if t.Completed != nil {
-		builder.WriteString("COMPLETED:" + timeToICal(*t.Completed) + lineBreak)
+		builder.WriteString("COMPLETED:" + timeToICal(t.Completed.UTC()) + "Z" + lineBreak)
 	}
 	if t.Priority != nil {
 		builder.WriteString(fmt.Sprintf("PRIORITY:%d%s", *t.Priority, lineBreak))
@@ -74,8 +115,8 @@ func (t *Todo) generate(builder *strings.Builder) error {
 		description := cleanDescription(t.Description)
 		builder.WriteString("DESCRIPTION:" + description + lineBreak)
 	}
-	if t.StartDate != nil {
-		builder.WriteString("DTSTART:" + timeToICal(*t.StartDate) + lineBreak)
+	if start != nil {
+		builder.WriteString(zonedTime("DTSTART", *start, t.TimeZone, opts) + lineBreak)
 	}
 	if t.Organizer.Email != "" {
 		err := t.Organizer.generateOrganizer(builder)
@@ -84,6 +125,20 @@ func (t *Todo) generate(builder *strings.Builder) error {
 		}
 	}
 
+	for _, attachment := range t.Attachments {
+		err := attachment.generate(builder)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, relation := range t.Relations {
+		err := relation.generate(builder)
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(t.Reminders) > 0 {
 		for _, reminder := range t.Reminders {
 			err := reminder.generate(builder)
@@ -94,7 +149,10 @@ func (t *Todo) generate(builder *strings.Builder) error {
 	}
 
 	if t.Recurrence != nil {
-		builder.WriteString(generateRRULE(t.Recurrence.Frequency, t.Recurrence.Day, t.Recurrence.EndTime))
+		builder.WriteString(t.rrule() + lineBreak)
+		for _, ex := range t.Recurrence.Exceptions {
+			builder.WriteString(zonedTime("EXDATE", t.exceptionStart(ex, *start), t.TimeZone, opts) + lineBreak)
+		}
 	}
 
 	builder.WriteString("END:VTODO" + lineBreak)
@@ -102,31 +160,269 @@ func (t *Todo) generate(builder *strings.Builder) error {
 }
 
 func (t *Todo) valid() bool {
-	if t.Summary == "" {
-		return false
-	}
+	return len(t.validationErrors()) == 0
+}
+
+// Validate explains why the To-Do is not valid, with a ValidationError for every broken rule.
+func (t *Todo) Validate() error {
+	return t.validationErrors().err()
+}
+
+func (t *Todo) validationErrors() ValidationErrors {
+	v := validation{sentinel: ErrInvalidTodo}
+	v.check(t.Summary != "", "Summary", "is required", nil)
+	v.check(t.Sequence >= 0, "Sequence", "can not be negative", t.Sequence)
 
 	if t.Recurrence != nil {
-		if t.Recurrence.Frequency != "" && t.Recurrence.Day < 0 {
-			return false
+		recurrenceErrs := t.Recurrence.validationErrors()
+		v.nest("Recurrence", recurrenceErrs)
+		v.checkClocks(t.Recurrence, t.TimeZone, "Recurrence")
+		if len(recurrenceErrs) == 0 {
+			first, err := t.firstInstance()
+			v.check(err == nil, "Recurrence", "needs a StartDate or Due date", nil)
+			v.check(err != nil || t.Recurrence.Until.IsZero() || !t.Recurrence.Until.Before(first),
+				"Recurrence.Until", "can not be before the first instance", t.Recurrence.Until)
 		}
 	}
 	if t.Priority != nil {
-		if *t.Priority < 1 || *t.Priority > 9 {
-			return false
+		v.check(*t.Priority >= 1 && *t.Priority <= 9, "Priority", "must be between 1 and 9", *t.Priority)
+	}
+	if t.PercentComplete != nil {
+		v.check(*t.PercentComplete >= 0 && *t.PercentComplete <= 100, "PercentComplete", "must be between 0 and 100", *t.PercentComplete)
+	}
+
+	v.check(t.Status == "" || t.Status.valid(), "Status", "is not a known To-Do status", t.Status)
+	if t.Status == CompletedStatus {
+		v.check(t.Completed != nil, "Completed", "is required when Status is COMPLETED", nil)
+		if t.PercentComplete != nil {
+			v.check(*t.PercentComplete == 100, "PercentComplete", "must be 100 when Status is COMPLETED", *t.PercentComplete)
 		}
+	} else if t.Completed != nil {
+		v.check(t.Status == "", "Completed", "can only be set when Status is COMPLETED, not "+string(t.Status), *t.Completed)
 	}
 
-	if t.Status != "" && !t.Status.valid() {
-		return false
+	if t.StartDate != nil && t.Due != nil {
+		v.check(!t.Due.Before(*t.StartDate), "Due", "can not be before StartDate", *t.Due)
+	}
+
+	if t.TimeZone != "" {
+		v.checkTimeZone(t.TimeZone, "TimeZone")
+	}
+
+	for i := range t.Reminders {
+		v.nest(indexPath("Reminders", i), t.Reminders[i].validationErrors())
+		// A reminder relative to the end needs a due date to be relative to
+		v.check(!t.Reminders[i].relatedToEnd() || t.Due != nil,
+			indexPath("Reminders", i)+".TriggerRelation", "END needs a Due date", nil)
+	}
+
+	for i := range t.Attachments {
+		v.nest(indexPath("Attachments", i), t.Attachments[i].validationErrors())
+	}
+
+	for i := range t.Relations {
+		v.nest(indexPath("Relations", i), t.Relations[i].validationErrors())
+	}
+	return v.errs
+}
+
+// Start marks the To-Do as in progress.
+func (t *Todo) Start() error {
+	if t.closed() {
+		return t.transitionError("start")
+	}
+	t.Status = InProcessStatus
+	if t.PercentComplete == nil {
+		percent := 0
+		t.PercentComplete = &percent
+	}
+	return nil
+}
+
+// Progress records the percentage of the To-Do that is complete, and marks it as in progress.
+//
+// Use Complete to finish the To-Do.
+func (t *Todo) Progress(percent int) error {
+	if t.closed() {
+		return t.transitionError("progress")
+	}
+	if percent < 0 || percent > 100 {
+		return &ValidationError{Path: "PercentComplete", Rule: "must be between 0 and 100", Value: percent, Err: ErrInvalidTodo}
+	}
+	t.Status = InProcessStatus
+	t.PercentComplete = &percent
+	return nil
+}
+
+// Complete marks the To-Do as completed at the given time.
+func (t *Todo) Complete(at time.Time) error {
+	if t.closed() {
+		return t.transitionError("complete")
 	}
-	return true
+	percent := 100
+	t.Status = CompletedStatus
+	t.Completed = &at
+	t.PercentComplete = &percent
+	return nil
+}
+
+// Cancel marks the To-Do as cancelled.
+func (t *Todo) Cancel() error {
+	if t.closed() {
+		return t.transitionError("cancel")
+	}
+	t.Status = CancelledStatus
+	t.Completed = nil
+	return nil
+}
+
+// Reopen marks a completed or cancelled To-Do as needing action again.
+func (t *Todo) Reopen() error {
+	if !t.closed() {
+		return t.transitionError("reopen")
+	}
+	t.Status = NeedsActionStatus
+	t.Completed = nil
+	t.PercentComplete = nil
+	return nil
+}
+
+// Whether the To-Do is completed or cancelled
+func (t *Todo) closed() bool {
+	return t.Status == CompletedStatus || t.Status == CancelledStatus
+}
+
+func (t *Todo) transitionError(action string) error {
+	status := t.Status
+	if status == "" {
+		status = NeedsActionStatus
+	}
+	return ErrInvalidTodoTransitionFrom(action, string(status))
 }
 
 func (t *Todo) uid() string {
+	if t.UID != "" {
+		return t.UID
+	}
 	return fmt.Sprintf("%s-%d@iCal.go", strings.ReplaceAll(t.Summary, " ", "_"), time.Now().Unix())
 }
 
+// CompleteOccurrence completes the current instance of a recurring To-Do.
+//
+// The completed instance is preserved as a To-Do of its own, a child of the series.
+// next is the series advanced to its following instance, or nil when the completed instance was the last one.
+func (t *Todo) CompleteOccurrence(at time.Time) (completed Todo, next *Todo, err error) {
+	if t.Recurrence == nil || !t.valid() {
+		return Todo{}, nil, ErrInvalidTodo
+	}
+	start, err := t.firstInstance()
+	if err != nil {
+		return Todo{}, nil, err
+	}
+	due := t.Recurrence.occurrenceEnd(start)
+	seriesUID := t.uid()
+	percent := 100
+
+	completed = *t
+	completed.UID = seriesUID + "-" + start.Format("20060102")
+	completed.Recurrence = nil
+	completed.StartDate = &start
+	completed.Due = &due
+	completed.Status = CompletedStatus
+	completed.Completed = &at
+	completed.PercentComplete = &percent
+	completed.Relations = append(append([]Relation{}, t.Relations...), Relation{UID: seriesUID, Type: ParentRelation})
+
+	nextStart, found := t.Recurrence.nextInstance(start)
+	if !found {
+		return completed, nil, nil
+	}
+	nextDue := t.Recurrence.occurrenceEnd(nextStart)
+
+	series := *t
+	series.UID = seriesUID
+	series.StartDate = &nextStart
+	series.Due = &nextDue
+	series.Completed = nil
+	series.PercentComplete = nil
+	if series.Status != "" {
+		series.Status = NeedsActionStatus
+	}
+	return completed, &series, nil
+}
+
+// Start of the first instance of a recurring To-Do
+func (t *Todo) firstInstance() (time.Time, error) {
+	start, err := t.seriesStart()
+	if err != nil {
+		return time.Time{}, err
+	}
+	for t.Recurrence.isException(start) {
+		start = t.Recurrence.advance(start)
+	}
+	return start, nil
+}
+
+// Start of the series of a recurring To-Do, the first instance when it is not an exception
+func (t *Todo) seriesStart() (time.Time, error) {
+	anchor := t.StartDate
+	if anchor == nil {
+		anchor = t.Due
+	}
+	if anchor == nil {
+		return time.Time{}, ErrInvalidTodo
+	}
+	date := t.TimeZone.localTime(*anchor)
+
+	r := t.Recurrence
+	start := time.Date(date.Year(), date.Month(), date.Day(),
+		r.StartTime.Hour(), r.StartTime.Minute(), r.StartTime.Second(), 0, date.Location())
+	if r.Frequency == WeeklyFrequency {
+		var err error
+		if start, err = findStartDate(date, r.Day, r.StartTime); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return start, nil
+}
+
+// Start of the first instance after start, skipping exception dates
+func (r *Recurrences) nextInstance(start time.Time) (time.Time, bool) {
+	next := r.advance(start)
+	for r.isException(next) {
+		next = r.advance(next)
+	}
+	if !r.Until.IsZero() && next.After(r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// The RRULE of a recurring To-Do
+//
+// Only weekly rules have a BYDAY, the others repeat on the date of DTSTART.
+func (t *Todo) rrule() string {
+	r := t.Recurrence
+	rule := "RRULE:FREQ=" + string(r.Frequency)
+	if r.Frequency == WeeklyFrequency {
+		rule += ";BYDAY=" + weekdayToICal(r.Day)
+	}
+	if !r.Until.IsZero() {
+		// UNTIL is in UTC when DTSTART has a time zone, floating otherwise
+		if t.TimeZone != "" {
+			rule += ";UNTIL=" + timeToICal(r.Until.UTC()) + "Z"
+		} else {
+			rule += ";UNTIL=" + timeToICal(r.Until)
+		}
+	}
+	return rule
+}
+
+// The start of the instance on the date of an exception
+func (t *Todo) exceptionStart(ex time.Time, start time.Time) time.Time {
+	return exceptionStart(t.TimeZone.localDate(ex), start)
+}
+
 type TodoStatus string
 
 const (
@@ -0,0 +1,219 @@
+package ical
+
+import (
+	"sort"
+	"time"
+)
+
+// OverdueTodos returns the open To-Dos that were due before at, earliest due first.
+//
+// Completed and cancelled To-Dos are never overdue.
+func (c *Calendar) OverdueTodos(at time.Time) []Todo {
+	var todos []Todo
+	for _, todo := range c.Todos {
+		due, hasDue := todo.dueDate()
+		if !todo.closed() && hasDue && due.Before(at) {
+			todos = append(todos, todo)
+		}
+	}
+	sortTodosByDue(todos)
+	return todos
+}
+
+// TodosDueWithin returns the open To-Dos due from `from` up to, but not including, `to`,
+// earliest due first.
+func (c *Calendar) TodosDueWithin(from, to time.Time) []Todo {
+	var todos []Todo
+	for _, todo := range c.Todos {
+		due, hasDue := todo.dueDate()
+		if !todo.closed() && hasDue && !due.Before(from) && due.Before(to) {
+			todos = append(todos, todo)
+		}
+	}
+	sortTodosByDue(todos)
+	return todos
+}
+
+// TodosByPriority returns every To-Do, highest priority first, then earliest due first.
+//
+// To-Dos without a priority or due date come after the others.
+func (c *Calendar) TodosByPriority() []Todo {
+	todos := append([]Todo{}, c.Todos...)
+	sortTodosByPriority(todos)
+	return todos
+}
+
+// TodosByStatus groups the To-Dos by status, keeping their order in the calendar.
+//
+// To-Dos without a status are grouped as NeedsActionStatus.
+func (c *Calendar) TodosByStatus() map[TodoStatus][]Todo {
+	groups := make(map[TodoStatus][]Todo)
+	for _, todo := range c.Todos {
+		status := todo.Status
+		if status == "" {
+			status = NeedsActionStatus
+		}
+		groups[status] = append(groups[status], todo)
+	}
+	return groups
+}
+
+// TodosInDependencyOrder orders the To-Dos so that every To-Do comes after the To-Dos it depends on,
+// following DEPENDS-ON relations. Independent To-Dos keep their order in the calendar.
+//
+// Returns ErrDanglingRelation when a To-Do depends on a UID that is not in the calendar,
+// and ErrRelationCycle when To-Dos depend on each other.
+func (c *Calendar) TodosInDependencyOrder() ([]Todo, error) {
+	dependencies, err := c.todoDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	// Kahn's algorithm, always taking the first ready To-Do in calendar order
+	remaining := make([]int, len(c.Todos))
+	dependents := make([][]int, len(c.Todos))
+	for i, deps := range dependencies {
+		remaining[i] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+
+	ordered := make([]Todo, 0, len(c.Todos))
+	added := make([]bool, len(c.Todos))
+	for len(ordered) < len(c.Todos) {
+		next := -1
+		for i := range c.Todos {
+			if !added[i] && remaining[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			for i := range c.Todos {
+				if !added[i] {
+					return nil, ErrRelationCycleAt(c.Todos[i].uid())
+				}
+			}
+		}
+
+		added[next] = true
+		ordered = append(ordered, c.Todos[next])
+		for _, dependent := range dependents[next] {
+			remaining[dependent]--
+		}
+	}
+	return ordered, nil
+}
+
+// NextTodos answers "what can I do next": the open To-Dos whose dependencies are all
+// completed or cancelled, and that have started by at.
+//
+// Sorted by priority, then due date.
+func (c *Calendar) NextTodos(at time.Time) ([]Todo, error) {
+	dependencies, err := c.todoDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []Todo
+	for i, todo := range c.Todos {
+		if todo.closed() || (todo.StartDate != nil && todo.StartDate.After(at)) {
+			continue
+		}
+		blocked := false
+		for _, dep := range dependencies[i] {
+			if !c.Todos[dep].closed() {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			todos = append(todos, todo)
+		}
+	}
+	sortTodosByPriority(todos)
+	return todos, nil
+}
+
+// DependsOn records that the To-Do can not be done before the To-Do with the UID.
+func (t *Todo) DependsOn(uid string) {
+	t.Relations = append(t.Relations, Relation{UID: uid, Type: DependsOnRelation})
+}
+
+// For every To-Do, the indexes of the To-Dos it depends on.
+//
+// Dependencies on other kinds of components are ignored.
+func (c *Calendar) todoDependencies() ([][]int, error) {
+	index := make(map[string]int, len(c.Todos))
+	for i := range c.Todos {
+		index[c.Todos[i].uid()] = i
+	}
+	known := make(map[string]bool)
+	for _, component := range c.components() {
+		known[component.uid()] = true
+	}
+
+	dependencies := make([][]int, len(c.Todos))
+	for i := range c.Todos {
+		for _, rel := range c.Todos[i].Relations {
+			if rel.Type != DependsOnRelation {
+				continue
+			}
+			if !known[rel.UID] {
+				return nil, ErrDanglingRelationFor(c.Todos[i].uid(), rel.UID)
+			}
+			if dep, isTodo := index[rel.UID]; isTodo {
+				dependencies[i] = append(dependencies[i], dep)
+			}
+		}
+	}
+	return dependencies, nil
+}
+
+// The due date of the To-Do, or of its current instance when it recurs
+func (t *Todo) dueDate() (time.Time, bool) {
+	if t.Recurrence != nil {
+		if start, err := t.firstInstance(); err == nil {
+			return t.Recurrence.occurrenceEnd(start), true
+		}
+	}
+	if t.Due == nil {
+		return time.Time{}, false
+	}
+	return *t.Due, true
+}
+
+func sortTodosByDue(todos []Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		return dueBefore(&todos[i], &todos[j])
+	})
+}
+
+func sortTodosByPriority(todos []Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		pi, pj := todos[i].priority(), todos[j].priority()
+		if pi != pj {
+			return pi < pj
+		}
+		return dueBefore(&todos[i], &todos[j])
+	})
+}
+
+// Whether a is due before b, To-Dos without a due date last
+func dueBefore(a, b *Todo) bool {
+	dueA, hasA := a.dueDate()
+	dueB, hasB := b.dueDate()
+	if hasA != hasB {
+		return hasA
+	}
+	return hasA && dueA.Before(dueB)
+}
+
+// The priority for sorting, 1 is the highest and an undefined priority is the lowest
+func (t *Todo) priority() int {
+	if t.Priority == nil || *t.Priority == 0 {
+		return 10
+	}
+	return *t.Priority
+}
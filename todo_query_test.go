@@ -0,0 +1,153 @@
+package ical
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOverdueAndDueWithin(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+	nextWeek := now.Add(7 * 24 * time.Hour)
+
+	cal := Create("Todos", "Todo queries")
+	cal.Todos = []Todo{
+		{UID: "late", Summary: "Late", Due: &yesterday},
+		{UID: "later", Summary: "Very late", Due: &lastWeek},
+		{UID: "done", Summary: "Done", Due: &yesterday, Status: CompletedStatus, Completed: &now},
+		{UID: "soon", Summary: "Soon", Due: &tomorrow},
+		{UID: "next-week", Summary: "Next week", Due: &nextWeek},
+		{UID: "someday", Summary: "Someday"},
+	}
+
+	if got := todoUIDs(cal.OverdueTodos(now)); !equalStrings(got, []string{"later", "late"}) {
+		t.Errorf("OverdueTodos() = %v, want [later late]", got)
+	}
+	if got := todoUIDs(cal.TodosDueWithin(now, now.Add(48*time.Hour))); !equalStrings(got, []string{"soon"}) {
+		t.Errorf("TodosDueWithin() = %v, want [soon]", got)
+	}
+}
+
+func TestTodosByPriority(t *testing.T) {
+	high, low := 1, 9
+	early := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(24 * time.Hour)
+
+	cal := Create("Todos", "Todo queries")
+	cal.Todos = []Todo{
+		{UID: "none", Summary: "No priority"},
+		{UID: "low", Summary: "Low", Priority: &low},
+		{UID: "high-late", Summary: "High late", Priority: &high, Due: &late},
+		{UID: "high-undated", Summary: "High undated", Priority: &high},
+		{UID: "high-early", Summary: "High early", Priority: &high, Due: &early},
+	}
+
+	want := []string{"high-early", "high-late", "high-undated", "low", "none"}
+	if got := todoUIDs(cal.TodosByPriority()); !equalStrings(got, want) {
+		t.Errorf("TodosByPriority() = %v, want %v", got, want)
+	}
+	if cal.Todos[0].UID != "none" {
+		t.Errorf("TodosByPriority() should not reorder the calendar")
+	}
+}
+
+func TestTodosByStatus(t *testing.T) {
+	cal := Create("Todos", "Todo queries")
+	cal.Todos = []Todo{
+		{UID: "a", Summary: "A"},
+		{UID: "b", Summary: "B", Status: InProcessStatus},
+		{UID: "c", Summary: "C", Status: NeedsActionStatus},
+	}
+
+	groups := cal.TodosByStatus()
+	if got := todoUIDs(groups[NeedsActionStatus]); !equalStrings(got, []string{"a", "c"}) {
+		t.Errorf("TodosByStatus()[NEEDS-ACTION] = %v, want [a c]", got)
+	}
+	if got := todoUIDs(groups[InProcessStatus]); !equalStrings(got, []string{"b"}) {
+		t.Errorf("TodosByStatus()[IN-PROCESS] = %v, want [b]", got)
+	}
+}
+
+func TestTodosInDependencyOrder(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	later := now.Add(24 * time.Hour)
+	high := 1
+
+	ship := Todo{UID: "ship", Summary: "Ship"}
+	ship.DependsOn("build")
+	ship.DependsOn("test")
+	build := Todo{UID: "build", Summary: "Build", Status: CompletedStatus, Completed: &now}
+	build.DependsOn("design")
+	test := Todo{UID: "test", Summary: "Test"}
+	test.DependsOn("build")
+
+	cal := Create("Todos", "Todo queries")
+	cal.Todos = []Todo{
+		ship,
+		build,
+		test,
+		{UID: "design", Summary: "Design", Status: CompletedStatus, Completed: &now},
+		{UID: "docs", Summary: "Docs", Priority: &high},
+		{UID: "blog", Summary: "Blog", StartDate: &later},
+	}
+
+	order, err := cal.TodosInDependencyOrder()
+	if err != nil {
+		t.Fatalf("TodosInDependencyOrder() returned error: %v", err)
+	}
+	want := []string{"design", "build", "test", "ship", "docs", "blog"}
+	if got := todoUIDs(order); !equalStrings(got, want) {
+		t.Errorf("TodosInDependencyOrder() = %v, want %v", got, want)
+	}
+
+	next, err := cal.NextTodos(now)
+	if err != nil {
+		t.Fatalf("NextTodos() returned error: %v", err)
+	}
+	if got := todoUIDs(next); !equalStrings(got, []string{"docs", "test"}) {
+		t.Errorf("NextTodos() = %v, want [docs test]", got)
+	}
+}
+
+func TestTodosInDependencyOrderErrors(t *testing.T) {
+	a := Todo{UID: "a", Summary: "A"}
+	a.DependsOn("b")
+	b := Todo{UID: "b", Summary: "B"}
+	b.DependsOn("a")
+
+	cal := Create("Todos", "Todo queries")
+	cal.Todos = []Todo{a, b}
+	if _, err := cal.TodosInDependencyOrder(); !errors.Is(err, ErrRelationCycle) {
+		t.Errorf("TodosInDependencyOrder() = %v, want %v", err, ErrRelationCycle)
+	}
+
+	dangling := Todo{UID: "c", Summary: "C"}
+	dangling.DependsOn("missing")
+	cal.Todos = []Todo{dangling}
+	if _, err := cal.NextTodos(time.Now()); !errors.Is(err, ErrDanglingRelation) {
+		t.Errorf("NextTodos() = %v, want %v", err, ErrDanglingRelation)
+	}
+}
+
+func todoUIDs(todos []Todo) []string {
+	uids := make([]string, len(todos))
+	for i, todo := range todos {
+		uids[i] = todo.UID
+	}
+	return uids
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,12 @@
 package ical
 
 import (
+	"errors"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/Tylerchristensen100/iCal/timezones"
 )
 
 func TestTodo(t *testing.T) {
@@ -57,7 +61,7 @@ func TestGenerateTodo(t *testing.T) {
 	todo := mockTodo()
 
 	var builder strings.Builder
-	err := todo.generate(&builder)
+	err := todo.generate(&builder, generateOptions{})
 	if err != nil {
 		t.Errorf("Error generating todo: %v", err)
 	}
@@ -85,7 +89,7 @@ func TestGenerateTodoWithReminder(t *testing.T) {
 	todo.Reminders = []Reminder{*reminder}
 
 	var builder strings.Builder
-	err := todo.generate(&builder)
+	err := todo.generate(&builder, generateOptions{})
 	if err != nil {
 		t.Errorf("Error generating todo with reminder: %v", err)
 	}
@@ -140,6 +144,46 @@ func TestTodoStatus(t *testing.T) {
 	}
 }
 
+func TestGenerateTodoWithTimeZone(t *testing.T) {
+	due := time.Date(2025, time.January, 6, 15, 0, 0, 0, time.UTC)
+	completed := time.Date(2025, time.January, 6, 10, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	todo := mockTodo()
+	todo.Due = &due
+	todo.Completed = &completed
+	todo.Status = CompletedStatus
+	todo.PercentComplete = nil
+	todo.TimeZone = TimeZone(timezones.America_New_York)
+
+	var builder strings.Builder
+	if err := todo.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("Error generating todo: %v", err)
+	}
+	output := builder.String()
+	for _, expected := range []string{
+		"DUE;TZID=America/New_York:20250106T100000\r\n",
+		"COMPLETED:20250106T150000Z\r\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generated todo missing %q: %s", expected, output)
+		}
+	}
+
+	// Without a time zone the due date is a floating local time
+	todo.TimeZone = ""
+	builder.Reset()
+	if err := todo.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("Error generating todo: %v", err)
+	}
+	if !strings.Contains(builder.String(), "DUE:20250106T150000\r\n") {
+		t.Errorf("Generated todo missing floating DUE: %s", builder.String())
+	}
+
+	todo.TimeZone = "Invalid/Timezone"
+	if todo.valid() {
+		t.Errorf("Expected todo with invalid time zone to be invalid")
+	}
+}
+
 func mockTodo() *Todo {
 	priority := 5
 	completePercent := 3
@@ -153,3 +197,221 @@ func mockTodo() *Todo {
 	}
 
 }
+
+func mockRecurringTodo() *Todo {
+	start := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC)
+	return &Todo{
+		UID:       "weekly-report@example.com",
+		Summary:   "Weekly report",
+		StartDate: &start,
+		Status:    NeedsActionStatus,
+		Recurrence: &Recurrences{
+			Frequency:  WeeklyFrequency,
+			Day:        time.Friday,
+			StartTime:  time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			EndTime:    time.Date(0, 1, 1, 17, 0, 0, 0, time.UTC),
+			Exceptions: []time.Time{time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)},
+			Until:      time.Date(2025, time.January, 31, 23, 59, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestGenerateRecurringTodo(t *testing.T) {
+	todo := mockRecurringTodo()
+
+	var builder strings.Builder
+	if err := todo.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("Error generating recurring todo: %v", err)
+	}
+	output := builder.String()
+	for _, expected := range []string{
+		"DTSTART:20250110T090000\r\n",
+		"DUE:20250110T170000\r\n",
+		"RRULE:FREQ=WEEKLY;BYDAY=FR;UNTIL=20250131T235900\r\n",
+		"EXDATE:20250117T090000\r\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Generated recurring todo missing %q: %s", expected, output)
+		}
+	}
+
+	// With a time zone, UNTIL is in UTC
+	todo.TimeZone = TimeZone(timezones.UTC)
+	builder.Reset()
+	if err := todo.generate(&builder, generateOptions{}); err != nil {
+		t.Fatalf("Error generating recurring todo: %v", err)
+	}
+	for _, expected := range []string{
+		"DTSTART;TZID=UTC:20250110T090000\r\n",
+		"UNTIL=20250131T235900Z\r\n",
+		"EXDATE;TZID=UTC:20250117T090000\r\n",
+	} {
+		if !strings.Contains(builder.String(), expected) {
+			t.Errorf("Generated recurring todo missing %q: %s", expected, builder.String())
+		}
+	}
+
+	// Monthly rules repeat on the date of DTSTART
+	todo.Recurrence.Frequency = MonthlyFrequency
+	if rule := todo.rrule(); rule != "RRULE:FREQ=MONTHLY;UNTIL=20250131T235900Z" {
+		t.Errorf("rrule() = %q; want no BYDAY for a monthly rule", rule)
+	}
+}
+
+func TestRecurringTodoValid(t *testing.T) {
+	todo := mockRecurringTodo()
+	if !todo.valid() {
+		t.Fatalf("Expected recurring todo to be valid")
+	}
+
+	noAnchor := mockRecurringTodo()
+	noAnchor.StartDate = nil
+	if noAnchor.valid() {
+		t.Errorf("Expected recurring todo without start or due date to be invalid")
+	}
+
+	untilBeforeStart := mockRecurringTodo()
+	untilBeforeStart.Recurrence.Until = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if untilBeforeStart.valid() {
+		t.Errorf("Expected recurring todo ending before its first instance to be invalid")
+	}
+
+	noEnd := mockRecurringTodo()
+	noEnd.Recurrence.EndTime = time.Time{}
+	if noEnd.valid() {
+		t.Errorf("Expected recurring todo without an instance end to be invalid")
+	}
+}
+
+func TestCompleteOccurrence(t *testing.T) {
+	todo := mockRecurringTodo()
+	completedAt := time.Date(2025, time.January, 10, 16, 0, 0, 0, time.UTC)
+
+	completed, next, err := todo.CompleteOccurrence(completedAt)
+	if err != nil {
+		t.Fatalf("CompleteOccurrence() returned error: %v", err)
+	}
+
+	if completed.Status != CompletedStatus || completed.Completed == nil || !completed.Completed.Equal(completedAt) {
+		t.Errorf("completed instance = %+v; want COMPLETED at %v", completed, completedAt)
+	}
+	if completed.Recurrence != nil || completed.UID != "weekly-report@example.com-20250110" {
+		t.Errorf("completed instance should be a single To-Do of its own, got UID %q", completed.UID)
+	}
+	if completed.Due == nil || !completed.Due.Equal(time.Date(2025, time.January, 10, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("completed instance due = %v; want the due date of the instance", completed.Due)
+	}
+	if len(completed.Relations) != 1 || completed.Relations[0].UID != todo.UID {
+		t.Errorf("completed instance relations = %v; want the series as parent", completed.Relations)
+	}
+
+	// January 17th is an exception, so the next instance is on the 24th
+	if next == nil {
+		t.Fatalf("CompleteOccurrence() returned no next instance")
+	}
+	if next.UID != todo.UID || next.Status != NeedsActionStatus || next.Completed != nil {
+		t.Errorf("next instance = %+v; want the open series", next)
+	}
+	expectedDue := time.Date(2025, time.January, 24, 17, 0, 0, 0, time.UTC)
+	if next.Due == nil || !next.Due.Equal(expectedDue) {
+		t.Errorf("next instance due = %v; want %v", next.Due, expectedDue)
+	}
+	if todo.StartDate.Day() != 6 {
+		t.Errorf("CompleteOccurrence() should not modify the original To-Do")
+	}
+
+	// The 31st is the last instance
+	_, next, err = next.CompleteOccurrence(completedAt)
+	if err != nil || next == nil {
+		t.Fatalf("CompleteOccurrence() = %v, %v; want the last instance", next, err)
+	}
+	_, next, err = next.CompleteOccurrence(completedAt)
+	if err != nil || next != nil {
+		t.Errorf("CompleteOccurrence() of the last instance = %v, %v; want no next instance", next, err)
+	}
+
+	single := mockTodo()
+	if _, _, err := single.CompleteOccurrence(completedAt); err != ErrInvalidTodo {
+		t.Errorf("CompleteOccurrence() of a single To-Do error = %v; want %v", err, ErrInvalidTodo)
+	}
+}
+
+func TestTodoValidate(t *testing.T) {
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+	ten, over := 10, 101
+
+	tests := []struct {
+		name   string
+		todo   Todo
+		reason string
+	}{
+		{"completed without timestamp", Todo{Summary: "x", Status: CompletedStatus}, "Completed is required when Status is COMPLETED"},
+		{"completed at 10%", Todo{Summary: "x", Status: CompletedStatus, Completed: &start, PercentComplete: &ten}, "PercentComplete must be 100 when Status is COMPLETED, got 10"},
+		{"cancelled with timestamp", Todo{Summary: "x", Status: CancelledStatus, Completed: &start}, "Completed can only be set when Status is COMPLETED, not CANCELLED"},
+		{"percent out of range", Todo{Summary: "x", PercentComplete: &over}, "PercentComplete must be between 0 and 100, got 101"},
+		{"due before start", Todo{Summary: "x", StartDate: &start, Due: &before}, "Due can not be before StartDate"},
+	}
+
+	for _, tt := range tests {
+		err := tt.todo.Validate()
+		if !errors.Is(err, ErrInvalidTodo) {
+			t.Errorf("%s: Validate() = %v; want %v", tt.name, err, ErrInvalidTodo)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.reason) {
+			t.Errorf("%s: Validate() = %q; want the reason %q", tt.name, err, tt.reason)
+		}
+	}
+
+	cal := Create("Todos", "")
+	if err := cal.AddTodo(Todo{Summary: "x", Status: CompletedStatus}); err == nil || !strings.Contains(err.Error(), "Completed is required") {
+		t.Errorf("AddTodo() = %v; want the reason the To-Do is invalid", err)
+	}
+}
+
+func TestTodoLifecycle(t *testing.T) {
+	todo := Todo{Summary: "Lifecycle"}
+	at := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+
+	if err := todo.Start(); err != nil || todo.Status != InProcessStatus || *todo.PercentComplete != 0 {
+		t.Fatalf("Start() = %v, status %s; want IN-PROCESS at 0%%", err, todo.Status)
+	}
+	if err := todo.Progress(40); err != nil || *todo.PercentComplete != 40 {
+		t.Fatalf("Progress(40) = %v, percent %d", err, *todo.PercentComplete)
+	}
+	var validationErr *ValidationError
+	if err := todo.Progress(120); !errors.Is(err, ErrInvalidTodo) || !errors.As(err, &validationErr) || validationErr.Path != "PercentComplete" || *todo.PercentComplete != 40 {
+		t.Errorf("Progress(120) = %v; want a PercentComplete ValidationError and no change", err)
+	}
+	if err := todo.Reopen(); !errors.Is(err, ErrInvalidTodoTransition) {
+		t.Errorf("Reopen() of an open To-Do = %v; want %v", err, ErrInvalidTodoTransition)
+	}
+
+	if err := todo.Complete(at); err != nil {
+		t.Fatalf("Complete() returned error: %v", err)
+	}
+	if todo.Status != CompletedStatus || !todo.Completed.Equal(at) || *todo.PercentComplete != 100 || !todo.valid() {
+		t.Errorf("Complete() left an inconsistent To-Do: %+v", todo)
+	}
+	for name, action := range map[string]func() error{
+		"Start":    todo.Start,
+		"Progress": func() error { return todo.Progress(50) },
+		"Complete": func() error { return todo.Complete(at) },
+		"Cancel":   todo.Cancel,
+	} {
+		if err := action(); !errors.Is(err, ErrInvalidTodoTransition) {
+			t.Errorf("%s() of a completed To-Do = %v; want %v", name, err, ErrInvalidTodoTransition)
+		}
+	}
+
+	if err := todo.Reopen(); err != nil || todo.Status != NeedsActionStatus || todo.Completed != nil || todo.PercentComplete != nil {
+		t.Errorf("Reopen() = %v; want an open To-Do, got %+v", err, todo)
+	}
+	if err := todo.Cancel(); err != nil || todo.Status != CancelledStatus || !todo.valid() {
+		t.Errorf("Cancel() = %v; want a valid cancelled To-Do, got %+v", err, todo)
+	}
+	if err := todo.Reopen(); err != nil || todo.Status != NeedsActionStatus {
+		t.Errorf("Reopen() of a cancelled To-Do = %v, status %s", err, todo.Status)
+	}
+}
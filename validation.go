@@ -0,0 +1,116 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a rule a field of a component breaks.
+//
+// It wraps the sentinel error of the field's component (e.g. ErrInvalidReminder),
+// and the sentinel errors of every component that contains it (e.g. ErrInvalidEvent, ErrInvalidCalendar).
+type ValidationError struct {
+	// Path to the field from the validated component, e.g. "Events[3].Reminders[0].Trigger"
+	Path string
+
+	// The rule that is broken, e.g. "is required"
+	Rule string
+
+	// The offending value, nil when the value is missing
+	Value any
+
+	// The sentinel error of the component the field belongs to
+	Err error
+
+	// The sentinel errors of the components containing it, innermost first
+	within []error
+}
+
+// ValidationErrors is every rule broken by a component, as returned by Validate.
+type ValidationErrors []*ValidationError
+
+func (e *ValidationError) Error() string {
+	msg := e.Rule
+	if e.Path != "" {
+		msg = e.Path + " " + msg
+	}
+	if e.Err != nil {
+		msg = e.Err.Error() + ": " + msg
+	}
+	if e.Value != nil {
+		msg += fmt.Sprintf(", got %v", e.Value)
+	}
+	return msg
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return append([]error{e.Err}, e.within...)
+}
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs ValidationErrors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// The errors as an error, nil when there are none
+func (errs ValidationErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Collects the rules broken by a component
+type validation struct {
+	// Sentinel error of the component
+	sentinel error
+	errs     ValidationErrors
+}
+
+// Records that the field at path breaks the rule, unless ok
+func (v *validation) check(ok bool, path, rule string, value any) {
+	v.checkErr(ok, v.sentinel, path, rule, value)
+}
+
+// Like check, with a more specific sentinel error than the component's, e.g. ErrInvalidEmail
+func (v *validation) checkErr(ok bool, err error, path, rule string, value any) {
+	if ok {
+		return
+	}
+	validationErr := &ValidationError{Path: path, Rule: rule, Value: value, Err: err}
+	if !errors.Is(v.sentinel, err) {
+		validationErr.within = []error{v.sentinel}
+	}
+	v.errs = append(v.errs, validationErr)
+}
+
+// Records the errors of the field at path, which is a component (or property) of its own
+func (v *validation) nest(path string, errs ValidationErrors) {
+	for _, err := range errs {
+		nested := *err
+		if nested.Path == "" {
+			nested.Path = path
+		} else {
+			nested.Path = path + "." + nested.Path
+		}
+		nested.within = append(append([]error{}, err.within...), v.sentinel)
+		v.errs = append(v.errs, &nested)
+	}
+}
+
+// Path of the element at index i of the field
+func indexPath(field string, i int) string {
+	return fmt.Sprintf("%s[%d]", field, i)
+}
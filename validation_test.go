@@ -0,0 +1,122 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateCalendar(t *testing.T) {
+	trigger := time.Date(2025, time.January, 6, 8, 0, 0, 0, time.UTC)
+	event := mockEvent()
+	event.Reminders = []Reminder{{
+		Description: "Leave now",
+		Action:      DisplayReminderAction,
+		TriggerAt:   &trigger,
+		Trigger:     -15 * time.Minute,
+	}}
+	event.Attendees = []Participant{{Name: "Bob", Email: "not-an-address"}}
+
+	cal := mockCalendar()
+	cal.Events = append(cal.Events, event)
+
+	err := cal.Validate()
+	if err == nil {
+		t.Fatalf("Validate() = nil, want errors")
+	}
+
+	var validationErrs ValidationErrors
+	if !errors.As(err, &validationErrs) || len(validationErrs) != 2 {
+		t.Fatalf("Validate() = %v, want 2 ValidationErrors", err)
+	}
+
+	var first *ValidationError
+	if !errors.As(err, &first) {
+		t.Fatalf("errors.As(%v, *ValidationError) = false", err)
+	}
+	if first.Path != "Events[2].Reminders[0].Trigger" || first.Rule != "can not be used with TriggerAt" || first.Value != -15*time.Minute {
+		t.Errorf("first error = %+v, want the trigger of the reminder", first)
+	}
+	for _, sentinel := range []error{ErrInvalidReminder, ErrInvalidEvent, ErrInvalidCalendar} {
+		if !errors.Is(first, sentinel) {
+			t.Errorf("errors.Is(%v, %v) = false", first, sentinel)
+		}
+	}
+	if errors.Is(first, ErrInvalidEmail) {
+		t.Errorf("errors.Is(%v, %v) = true, want false", first, ErrInvalidEmail)
+	}
+
+	second := validationErrs[1]
+	if second.Path != "Events[2].Attendees[0].Email" || second.Value != "not-an-address" {
+		t.Errorf("second error = %+v, want the email of the attendee", second)
+	}
+	for _, sentinel := range []error{ErrInvalidEmail, ErrInvalidParticipant, ErrInvalidEvent, ErrInvalidCalendar} {
+		if !errors.Is(second, sentinel) {
+			t.Errorf("errors.Is(%v, %v) = false", second, sentinel)
+		}
+	}
+
+	expected := "invalid reminder: Events[2].Reminders[0].Trigger can not be used with TriggerAt, got -15m0s; " +
+		"invalid email format: Events[2].Attendees[0].Email is not a valid calendar address, got not-an-address"
+	if err.Error() != expected {
+		t.Errorf("Validate() = %q, want %q", err, expected)
+	}
+
+	if _, err := cal.Generate(); !errors.Is(err, ErrInvalidCalendar) || !strings.Contains(err.Error(), "Reminders[0].Trigger") {
+		t.Errorf("Generate() = %v, want the validation errors", err)
+	}
+}
+
+func TestValidateValid(t *testing.T) {
+	cal := mockCalendar()
+	if err := cal.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	event := mockEvent()
+	if err := event.Validate(); err != nil {
+		t.Errorf("Event.Validate() = %v, want nil", err)
+	}
+	if err := mockJournal().Validate(); err != nil {
+		t.Errorf("Journal.Validate() = %v, want nil", err)
+	}
+	if err := mockTodo().Validate(); err != nil {
+		t.Errorf("Todo.Validate() = %v, want nil", err)
+	}
+}
+
+func TestValidateComponents(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		sentinel error
+		paths    []string
+	}{
+		{"empty calendar", (&Calendar{}).Validate(), ErrInvalidCalendar, []string{"Name", ""}},
+		{"event", (&Event{StartDate: time.Now(), EndDate: time.Now().Add(-time.Hour)}).Validate(), ErrInvalidEvent, []string{"Title", "EndDate", "TimeZone"}},
+		{"journal", (&Journal{Categories: []string{""}}).Validate(), ErrInvalidJournal, []string{"Summary", "Description", "Categories[0]"}},
+		{"recurrence", (&Recurrences{Frequency: "HOURLY", Day: 9}).Validate(), ErrInvalidRecurrence, []string{"Frequency", "Day", "EndTime"}},
+		{"reminder", (&Reminder{Action: EmailReminderAction}).Validate(), ErrInvalidReminder, []string{"Description", "Attendees"}},
+		{"todo", (&Todo{Summary: "x", Relations: []Relation{{Type: "COUSIN"}}}).Validate(), ErrInvalidTodo, []string{"Relations[0].UID", "Relations[0].Type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.sentinel) {
+				t.Errorf("Validate() = %v, want %v", tt.err, tt.sentinel)
+			}
+			var errs ValidationErrors
+			if !errors.As(tt.err, &errs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", tt.err)
+			}
+			var paths []string
+			for _, err := range errs {
+				paths = append(paths, err.Path)
+			}
+			if !equalStrings(paths, tt.paths) {
+				t.Errorf("Validate() paths = %q, want %q", paths, tt.paths)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package ical
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reads the VTIMEZONEs of the calendars, so TZIDs that are not known time zones,
+// such as Outlook's "Customized Time Zone", are read with the offsets the data defines
+func (p *parser) readTimeZones(calendars []*rawComponent) {
+	p.timeZones = make(map[string]*time.Location)
+	for _, calendar := range calendars {
+		for _, child := range calendar.Components {
+			if child.Name != "VTIMEZONE" {
+				continue
+			}
+			tzid, found := child.property("TZID")
+			if !found {
+				continue
+			}
+			if loc, ok := vtimezoneLocation(tzid.Value, child); ok {
+				p.timeZones[tzid.Value] = loc
+			}
+		}
+	}
+}
+
+// An observance of a VTIMEZONE, a STANDARD or DAYLIGHT component
+type observance struct {
+	name   string
+	offset int
+	start  time.Time
+
+	// The yearly rule of the observance as a POSIX TZ rule, e.g. "M3.2.0/02:00:00", empty without one
+	rule string
+}
+
+// A *time.Location following the VTIMEZONE
+//
+// The latest STANDARD and DAYLIGHT observances apply to every year. Observances recurring on
+// the nth weekday of a month, as nearly every time zone does, keep their daylight saving time,
+// other time zones are read with the offset of their standard time.
+func vtimezoneLocation(tzid string, c *rawComponent) (*time.Location, bool) {
+	var standard, daylight *observance
+	for _, child := range c.Components {
+		o, ok := readObservance(child)
+		if !ok {
+			continue
+		}
+		switch child.Name {
+		case "STANDARD":
+			if standard == nil || o.start.After(standard.start) {
+				standard = o
+			}
+		case "DAYLIGHT":
+			if daylight == nil || o.start.After(daylight.start) {
+				daylight = o
+			}
+		}
+	}
+	if standard == nil {
+		standard, daylight = daylight, nil
+	}
+	if standard == nil {
+		return nil, false
+	}
+	if daylight == nil || standard.rule == "" || daylight.rule == "" {
+		return time.FixedZone(tzid, standard.offset), true
+	}
+
+	footer := fmt.Sprintf("<%s>%s<%s>%s,%s,%s", standard.name, posixOffset(standard.offset),
+		daylight.name, posixOffset(daylight.offset), daylight.rule, standard.rule)
+	loc, err := time.LoadLocationFromTZData(tzid, tzifData(standard, footer))
+	if err != nil {
+		return time.FixedZone(tzid, standard.offset), true
+	}
+	return loc, true
+}
+
+func readObservance(c *rawComponent) (*observance, bool) {
+	if c.Name != "STANDARD" && c.Name != "DAYLIGHT" {
+		return nil, false
+	}
+	offsetTo, found := c.property("TZOFFSETTO")
+	if !found {
+		return nil, false
+	}
+	offset, ok := utcOffset(offsetTo.Value)
+	if !ok {
+		return nil, false
+	}
+
+	o := &observance{name: c.Name[:3], offset: offset}
+	if name, found := c.property("TZNAME"); found && name.Value != "" && !strings.ContainsAny(name.Value, "<>,") {
+		o.name = name.Value
+	}
+	if start, found := c.property("DTSTART"); found {
+		o.start, _ = time.Parse(iCalTimeLayout, start.Value)
+	}
+	if rrule, found := c.property("RRULE"); found {
+		o.rule = posixRule(rrule.Value, o.start)
+	}
+	return o, true
+}
+
+// The UTC offset of a TZOFFSETFROM or TZOFFSETTO value, e.g. "-0500", in seconds
+func utcOffset(value string) (int, bool) {
+	if len(value) != 5 && len(value) != 7 || (value[0] != '+' && value[0] != '-') {
+		return 0, false
+	}
+	seconds := 0
+	for i, unit := range []int{3600, 60, 1} {
+		if 1+2*i >= len(value) {
+			break
+		}
+		n, err := strconv.Atoi(value[1+2*i : 3+2*i])
+		if err != nil {
+			return 0, false
+		}
+		seconds += n * unit
+	}
+	if value[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, true
+}
+
+// The offset of a POSIX TZ string, which is west of UTC, e.g. "5" for UTC-05:00
+func posixOffset(offset int) string {
+	sign := "-"
+	if offset <= 0 {
+		sign, offset = "", -offset
+	}
+	return fmt.Sprintf("%s%d:%02d:%02d", sign, offset/3600, offset/60%60, offset%60)
+}
+
+// The POSIX TZ rule of a yearly RRULE on the nth weekday of a month, e.g. "M3.2.0/02:00:00",
+// empty when the RRULE is not one
+func posixRule(rrule string, start time.Time) string {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rrule, ";") {
+		name, value, _ := strings.Cut(part, "=")
+		parts[strings.ToUpper(name)] = strings.ToUpper(value)
+	}
+	if parts["FREQ"] != "YEARLY" || parts["UNTIL"] != "" || parts["COUNT"] != "" || parts["BYMONTHDAY"] != "" {
+		return ""
+	}
+	month, err := strconv.Atoi(parts["BYMONTH"])
+	if err != nil || month < 1 || month > 12 {
+		return ""
+	}
+
+	byDay := parts["BYDAY"]
+	if len(byDay) < 3 {
+		return ""
+	}
+	day, err := DayOfWeekFromString(byDay[len(byDay)-2:])
+	if err != nil {
+		return ""
+	}
+	week, err := strconv.Atoi(byDay[:len(byDay)-2])
+	switch {
+	case err != nil || week == 0 || week < -1 || week > 5:
+		return ""
+	case week == -1:
+		week = 5
+	}
+	return fmt.Sprintf("M%d.%d.%d/%02d:%02d:%02d", month, week, day, start.Hour(), start.Minute(), start.Second())
+}
+
+// TZif data (RFC 8536) of a time zone without transitions, following the POSIX TZ string in its footer
+func tzifData(standard *observance, footer string) []byte {
+	var data []byte
+	// The version 1 data, then the same data again for version 2
+	for range 2 {
+		data = append(data, "TZif2"...)
+		data = append(data, make([]byte, 15)...)
+		for _, count := range []int{0, 0, 0, 0, 1, len(standard.name) + 1} {
+			data = binary.BigEndian.AppendUint32(data, uint32(count))
+		}
+		data = binary.BigEndian.AppendUint32(data, uint32(int32(standard.offset)))
+		data = append(data, 0, 0)
+		data = append(data, standard.name...)
+		data = append(data, 0)
+	}
+	return append(data, "\n"+footer+"\n"...)
+}
@@ -0,0 +1,149 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// A VTIMEZONE as Outlook exports it for a zone it has no name for
+var customTimeZone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:Customized Time Zone",
+	"BEGIN:STANDARD",
+	"DTSTART:16010101T020000",
+	"TZOFFSETFROM:-0500",
+	"TZOFFSETTO:-0600",
+	"RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=1SU;BYMONTH=11",
+	"END:STANDARD",
+	"BEGIN:DAYLIGHT",
+	"DTSTART:16010101T020000",
+	"TZOFFSETFROM:-0600",
+	"TZOFFSETTO:-0500",
+	"RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=2SU;BYMONTH=3",
+	"END:DAYLIGHT",
+	"END:VTIMEZONE",
+}
+
+func TestVtimezoneLocation(t *testing.T) {
+	timeZone := roundTripCalendar(t, append(append([]string{"BEGIN:VCALENDAR"}, customTimeZone...), "END:VCALENDAR")...).source.raw.Components[0]
+	loc, ok := vtimezoneLocation("Customized Time Zone", timeZone)
+	if !ok {
+		t.Fatalf("vtimezoneLocation() = false, want the location")
+	}
+
+	var tests = []struct {
+		local    time.Time
+		expected time.Time
+	}{
+		{time.Date(2025, time.January, 6, 9, 0, 0, 0, loc), time.Date(2025, time.January, 6, 15, 0, 0, 0, time.UTC)},
+		{time.Date(2025, time.March, 9, 1, 59, 0, 0, loc), time.Date(2025, time.March, 9, 7, 59, 0, 0, time.UTC)},
+		{time.Date(2025, time.March, 9, 3, 0, 0, 0, loc), time.Date(2025, time.March, 9, 8, 0, 0, 0, time.UTC)},
+		{time.Date(2025, time.July, 1, 9, 0, 0, 0, loc), time.Date(2025, time.July, 1, 14, 0, 0, 0, time.UTC)},
+		{time.Date(2025, time.November, 2, 3, 0, 0, 0, loc), time.Date(2025, time.November, 2, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if !tt.local.Equal(tt.expected) {
+			t.Errorf("%s = %s UTC, want %s", tt.local.Format(iCalTimeLayout), tt.local.UTC().Format(iCalTimeLayout), tt.expected.Format(iCalTimeLayout))
+		}
+	}
+}
+
+func TestVtimezoneLocationFixed(t *testing.T) {
+	timeZone := &rawComponent{Name: "VTIMEZONE", Components: []*rawComponent{{
+		Name: "STANDARD",
+		Properties: []rawProperty{
+			newRawProperty("DTSTART:19700101T000000"),
+			newRawProperty("TZOFFSETFROM:+0530"),
+			newRawProperty("TZOFFSETTO:+0530"),
+		},
+	}}}
+	loc, ok := vtimezoneLocation("India", timeZone)
+	if !ok {
+		t.Fatalf("vtimezoneLocation() = false, want the location")
+	}
+	if _, offset := time.Date(2025, time.July, 1, 0, 0, 0, 0, loc).Zone(); offset != 5*3600+30*60 {
+		t.Errorf("offset = %d, want 19800", offset)
+	}
+
+	if _, ok := vtimezoneLocation("Empty", &rawComponent{Name: "VTIMEZONE"}); ok {
+		t.Errorf("vtimezoneLocation() = true, want false without observances")
+	}
+}
+
+func TestUTCOffset(t *testing.T) {
+	var tests = []struct {
+		value    string
+		expected int
+		ok       bool
+	}{
+		{"+0100", 3600, true},
+		{"-0500", -5 * 3600, true},
+		{"+053000", 5*3600 + 30*60, true},
+		{"+0545", 5*3600 + 45*60, true},
+		{"0100", 0, false},
+		{"+1", 0, false},
+		{"+01xx", 0, false},
+	}
+	for _, tt := range tests {
+		offset, ok := utcOffset(tt.value)
+		if offset != tt.expected || ok != tt.ok {
+			t.Errorf("utcOffset(%q) = %d, %v, want %d, %v", tt.value, offset, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestPosixRule(t *testing.T) {
+	start := time.Date(1970, time.January, 1, 2, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		rrule    string
+		expected string
+	}{
+		{"FREQ=YEARLY;BYMONTH=3;BYDAY=2SU", "M3.2.0/02:00:00"},
+		{"FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10", "M10.5.0/02:00:00"},
+		{"FREQ=YEARLY;BYMONTH=3;BYDAY=SU;BYMONTHDAY=8,9,10,11,12,13,14", ""},
+		{"FREQ=YEARLY;BYMONTH=4;BYDAY=1SU;UNTIL=20060402T070000Z", ""},
+		{"FREQ=MONTHLY;BYDAY=1SU", ""},
+	}
+	for _, tt := range tests {
+		if rule := posixRule(tt.rrule, start); rule != tt.expected {
+			t.Errorf("posixRule(%q) = %q, want %q", tt.rrule, rule, tt.expected)
+		}
+	}
+}
+
+func TestParseCustomTimeZone(t *testing.T) {
+	lines := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//Example//EN"}, customTimeZone...)
+	lines = append(lines,
+		"BEGIN:VEVENT",
+		"UID:planning@example.com",
+		"SUMMARY:Planning",
+		"DTSTART;TZID=Customized Time Zone:20250310T090000",
+		"DTEND;TZID=Customized Time Zone:20250310T100000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	data := calendarData(lines...)
+
+	cal, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	event := cal.Events[0]
+	if !event.StartDate.Equal(time.Date(2025, time.March, 10, 14, 0, 0, 0, time.UTC)) || event.TimeZone != "UTC" {
+		t.Errorf("StartDate = %v in %s, want 14:00 UTC", event.StartDate, event.TimeZone)
+	}
+	if output := generateString(t, cal); output != data {
+		t.Errorf("Generate() =\n%s\nwant\n%s", output, data)
+	}
+
+	_, diagnostics, err := ParseLenient(strings.NewReader(data))
+	if err != nil || len(diagnostics) != 0 {
+		t.Errorf("ParseLenient() = %v, %v, want no diagnostics", diagnostics, err)
+	}
+
+	undefined := strings.Replace(data, "TZID:Customized Time Zone", "TZID:Other Time Zone", 1)
+	if _, err := Parse(strings.NewReader(undefined)); err == nil || !strings.Contains(err.Error(), "unknown time zone") {
+		t.Errorf("Parse() = %v, want the unknown time zone", err)
+	}
+}